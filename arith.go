@@ -0,0 +1,39 @@
+package wide
+
+import "math/bits"
+
+// div128by64Generic returns the quotient and remainder of (hi, lo) / d, which must satisfy hi < d
+func div128by64Generic(hi, lo, d uint64) (q, r uint64) {
+	return bits.Div64(hi, lo, d)
+}
+
+// mul128Generic returns the lower 128 bits of the product of (xHi, xLo) and (yHi, yLo)
+func mul128Generic(xHi, xLo, yHi, yLo uint64) (hi, lo uint64) {
+	hi, lo = bits.Mul64(xLo, yLo)
+	hi += xHi*yLo + xLo*yHi
+	return hi, lo
+}
+
+// shl128Generic returns (hi, lo) shifted left by n bits
+func shl128Generic(hi, lo uint64, n uint) (zHi, zLo uint64) {
+	switch {
+	case n >= int128Size:
+		return 0, 0
+	case n >= int64Size:
+		return lo << (n - int64Size), 0
+	default:
+		return hi<<n | lo>>(int64Size-n), lo << n
+	}
+}
+
+// shr128Generic returns (hi, lo) shifted right by n bits
+func shr128Generic(hi, lo uint64, n uint) (zHi, zLo uint64) {
+	switch {
+	case n >= int128Size:
+		return 0, 0
+	case n >= int64Size:
+		return 0, hi >> (n - int64Size)
+	default:
+		return hi >> n, lo>>n | hi<<(int64Size-n)
+	}
+}
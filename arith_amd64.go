@@ -0,0 +1,23 @@
+//go:build amd64 && !purego
+
+package wide
+
+// div128by64 returns the quotient and remainder of (hi, lo) / d, which must satisfy hi < d, using DIVQ
+//
+//go:noescape
+func div128by64(hi, lo, d uint64) (q, r uint64)
+
+// mul128 returns the lower 128 bits of the product of (xHi, xLo) and (yHi, yLo), using MULQ
+//
+//go:noescape
+func mul128(xHi, xLo, yHi, yLo uint64) (hi, lo uint64)
+
+// shl128 returns (hi, lo) shifted left by n bits, using SHLD
+//
+//go:noescape
+func shl128(hi, lo uint64, n uint) (zHi, zLo uint64)
+
+// shr128 returns (hi, lo) shifted right by n bits, using SHRD
+//
+//go:noescape
+func shr128(hi, lo uint64, n uint) (zHi, zLo uint64)
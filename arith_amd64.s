@@ -0,0 +1,80 @@
+//go:build amd64 && !purego
+
+#include "textflag.h"
+
+// func div128by64(hi, lo, d uint64) (q, r uint64)
+TEXT ·div128by64(SB), NOSPLIT, $0-40
+	MOVQ hi+0(FP), DX
+	MOVQ lo+8(FP), AX
+	MOVQ d+16(FP), CX
+	DIVQ CX
+	MOVQ AX, q+24(FP)
+	MOVQ DX, r+32(FP)
+	RET
+
+// func mul128(xHi, xLo, yHi, yLo uint64) (hi, lo uint64)
+TEXT ·mul128(SB), NOSPLIT, $0-48
+	MOVQ xLo+8(FP), AX
+	MOVQ yLo+24(FP), CX
+	MULQ CX
+	// The cross products only contribute to the upper half
+	MOVQ xHi+0(FP), BX
+	IMULQ CX, BX
+	ADDQ BX, DX
+	MOVQ yHi+16(FP), BX
+	MOVQ xLo+8(FP), SI
+	IMULQ SI, BX
+	ADDQ BX, DX
+	MOVQ DX, hi+32(FP)
+	MOVQ AX, lo+40(FP)
+	RET
+
+// func shl128(hi, lo uint64, n uint) (zHi, zLo uint64)
+TEXT ·shl128(SB), NOSPLIT, $0-40
+	MOVQ hi+0(FP), DX
+	MOVQ lo+8(FP), AX
+	MOVQ n+16(FP), CX
+	CMPQ CX, $128
+	JAE zero
+	CMPQ CX, $64
+	JAE wide
+	SHLQ CX, AX, DX
+	SHLQ CX, AX
+	MOVQ DX, zHi+24(FP)
+	MOVQ AX, zLo+32(FP)
+	RET
+wide:
+	SUBQ $64, CX
+	SHLQ CX, AX
+	MOVQ AX, zHi+24(FP)
+	MOVQ $0, zLo+32(FP)
+	RET
+zero:
+	MOVQ $0, zHi+24(FP)
+	MOVQ $0, zLo+32(FP)
+	RET
+
+// func shr128(hi, lo uint64, n uint) (zHi, zLo uint64)
+TEXT ·shr128(SB), NOSPLIT, $0-40
+	MOVQ hi+0(FP), DX
+	MOVQ lo+8(FP), AX
+	MOVQ n+16(FP), CX
+	CMPQ CX, $128
+	JAE zero
+	CMPQ CX, $64
+	JAE wide
+	SHRQ CX, DX, AX
+	SHRQ CX, DX
+	MOVQ DX, zHi+24(FP)
+	MOVQ AX, zLo+32(FP)
+	RET
+wide:
+	SUBQ $64, CX
+	SHRQ CX, DX
+	MOVQ $0, zHi+24(FP)
+	MOVQ DX, zLo+32(FP)
+	RET
+zero:
+	MOVQ $0, zHi+24(FP)
+	MOVQ $0, zLo+32(FP)
+	RET
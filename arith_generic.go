@@ -0,0 +1,23 @@
+//go:build !amd64 || purego
+
+package wide
+
+// div128by64 returns the quotient and remainder of (hi, lo) / d, which must satisfy hi < d
+func div128by64(hi, lo, d uint64) (q, r uint64) {
+	return div128by64Generic(hi, lo, d)
+}
+
+// mul128 returns the lower 128 bits of the product of (xHi, xLo) and (yHi, yLo)
+func mul128(xHi, xLo, yHi, yLo uint64) (hi, lo uint64) {
+	return mul128Generic(xHi, xLo, yHi, yLo)
+}
+
+// shl128 returns (hi, lo) shifted left by n bits
+func shl128(hi, lo uint64, n uint) (zHi, zLo uint64) {
+	return shl128Generic(hi, lo, n)
+}
+
+// shr128 returns (hi, lo) shifted right by n bits
+func shr128(hi, lo uint64, n uint) (zHi, zLo uint64) {
+	return shr128Generic(hi, lo, n)
+}
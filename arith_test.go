@@ -0,0 +1,93 @@
+package wide
+
+import (
+	"math/big"
+	"math/rand/v2"
+	"testing"
+)
+
+// arithKernels are the implementations of the arithmetic kernels which are tested against the same vectors
+//
+// On amd64 without the purego build tag, the native kernels are written in assembly, and otherwise they are the generic ones.
+var arithKernels = []struct {
+	name       string
+	div128by64 func(hi, lo, d uint64) (q, r uint64)
+	mul128     func(xHi, xLo, yHi, yLo uint64) (hi, lo uint64)
+	shl128     func(hi, lo uint64, n uint) (zHi, zLo uint64)
+	shr128     func(hi, lo uint64, n uint) (zHi, zLo uint64)
+}{
+	{"generic", div128by64Generic, mul128Generic, shl128Generic, shr128Generic},
+	{"native", div128by64, mul128, shl128, shr128},
+}
+
+// arithVectors returns edge cases followed by pseudo-random Uint128's of every bit length
+func arithVectors() []Uint128 {
+	xs := []Uint128{
+		{}, {lo: 1}, {lo: 2}, {lo: maxUint64}, {hi: 1}, {hi: 1, lo: 1}, {hi: 1 << 63}, {hi: maxInt64, lo: maxUint64},
+		{hi: maxUint64, lo: maxUint64}, {hi: maxUint64, lo: maxUint64 - 1}, {hi: 0x0123456789abcdef, lo: 0xfedcba9876543210},
+	}
+	r := rand.New(rand.NewPCG(1, 2))
+	for n := uint(1); n <= int128Size; n++ {
+		x := NewUint128(r.Uint64(), r.Uint64()).RShiftN(int128Size - n)
+		xs = append(xs, x.Or(Uint128{lo: 1}.LShiftN(n-1)))
+	}
+	return xs
+}
+
+func TestArithKernels(t *testing.T) {
+	mod := new(big.Int).Lsh(big.NewInt(1), int128Size)
+	xs := arithVectors()
+	for _, k := range arithKernels {
+		for _, x := range xs {
+			for _, y := range xs {
+				hi, lo := k.mul128(x.hi, x.lo, y.hi, y.lo)
+				expected := new(big.Int).Mul(bigUint128(x), bigUint128(y))
+				if result := (Uint128{hi: hi, lo: lo}); bigUint128(result).Cmp(expected.Mod(expected, mod)) != 0 {
+					t.Fatalf("Expected %s mul128(%s, %s) == %#x, got: %s", k.name, x, y, expected, result)
+				}
+				if y.hi == 0 && y.lo != 0 && x.hi < y.lo {
+					q, r := k.div128by64(x.hi, x.lo, y.lo)
+					bq, br := new(big.Int).QuoRem(bigUint128(x), bigUint128(y), new(big.Int))
+					if bq.Uint64() != q || br.Uint64() != r {
+						t.Fatalf("Expected %s div128by64(%s, %s) == %#x, %#x, got: %#x, %#x", k.name, x, y, bq, br, q, r)
+					}
+				}
+			}
+			for n := uint(0); n <= 2*int128Size; n++ {
+				hi, lo := k.shl128(x.hi, x.lo, n)
+				expected := new(big.Int).Lsh(bigUint128(x), n)
+				if result := (Uint128{hi: hi, lo: lo}); bigUint128(result).Cmp(expected.Mod(expected, mod)) != 0 {
+					t.Fatalf("Expected %s shl128(%s, %d) == %#x, got: %s", k.name, x, n, expected, result)
+				}
+				hi, lo = k.shr128(x.hi, x.lo, n)
+				expected = new(big.Int).Rsh(bigUint128(x), n)
+				if result := (Uint128{hi: hi, lo: lo}); bigUint128(result).Cmp(expected) != 0 {
+					t.Fatalf("Expected %s shr128(%s, %d) == %#x, got: %s", k.name, x, n, expected, result)
+				}
+			}
+		}
+	}
+}
+
+func TestDivModUint128Big(t *testing.T) {
+	xs := arithVectors()
+	for _, x := range xs {
+		for _, d := range xs {
+			if d.hi == 0 && d.lo == 0 {
+				continue
+			}
+			q, r := x.DivMod(d)
+			bq, br := new(big.Int).QuoRem(bigUint128(x), bigUint128(d), new(big.Int))
+			if bigUint128(q).Cmp(bq) != 0 || bigUint128(r).Cmp(br) != 0 {
+				t.Fatalf("Expected %s.DivMod(%s) == %#x, %#x, got: %s, %s", x, d, bq, br, q, r)
+			}
+		}
+	}
+}
+
+func BenchmarkDivModUint128(b *testing.B) {
+	x, d := Uint128{hi: 0x0123456789abcdef, lo: 0xfedcba9876543210}, Uint128{hi: 0xdeadbeef, lo: 0xbaadf00d}
+	for i := 0; i < b.N; i++ {
+		x, _ = x.Add(Uint128{hi: 0x0123456789abcdef}).DivMod(d)
+	}
+}
@@ -0,0 +1,91 @@
+package wide
+
+// AtomicInt128 is an Int128 which can be read and written atomically
+//
+// It is implemented in the same way as AtomicUint128, including its fallbacks. The zero value is 0. An AtomicInt128 must not
+// be copied after first use.
+type AtomicInt128 struct {
+	_ noCopy
+	v atomic128
+}
+
+// AtomicUint128 is a Uint128 which can be read and written atomically
+//
+// On amd64 it is lock-free, using the CMPXCHG16B instruction, or a striped lock on the few CPUs which lack it. It falls back
+// to a seqlock, which never blocks readers unless a write is in progress, on other architectures, with the purego build tag,
+// and under the race detector, which cannot observe the memory accesses of assembly. Race-enabled tests therefore check the
+// seqlock rather than CMPXCHG16B, which is stress tested by the ordinary tests on amd64. The zero value is 0. An AtomicUint128
+// must not be copied after first use.
+type AtomicUint128 struct {
+	_ noCopy
+	v atomic128
+}
+
+// noCopy may be embedded into structs which must not be copied after first use, which is detected by go vet's copylocks checker
+type noCopy struct{}
+
+// Lock is a no-op used by go vet's copylocks checker
+func (*noCopy) Lock() {}
+
+// Unlock is a no-op used by go vet's copylocks checker
+func (*noCopy) Unlock() {}
+
+// Add atomically adds delta to an AtomicInt128, and returns the new value
+//
+// This function overflows silently.
+func (a *AtomicInt128) Add(delta Int128) (new Int128) {
+	hi, lo := a.v.add(uint64(delta.hi), delta.lo)
+	return Int128{hi: int64(hi), lo: lo}
+}
+
+// CompareAndSwap atomically replaces the value of an AtomicInt128 with new, if it is equal to old
+func (a *AtomicInt128) CompareAndSwap(old, new Int128) (swapped bool) {
+	return a.v.cas(uint64(old.hi), old.lo, uint64(new.hi), new.lo)
+}
+
+// Load atomically loads the value of an AtomicInt128
+func (a *AtomicInt128) Load() Int128 {
+	hi, lo := a.v.load()
+	return Int128{hi: int64(hi), lo: lo}
+}
+
+// Store atomically stores a value into an AtomicInt128
+func (a *AtomicInt128) Store(val Int128) {
+	a.v.swap(uint64(val.hi), val.lo)
+}
+
+// Swap atomically stores new into an AtomicInt128, and returns the old value
+func (a *AtomicInt128) Swap(new Int128) (old Int128) {
+	hi, lo := a.v.swap(uint64(new.hi), new.lo)
+	return Int128{hi: int64(hi), lo: lo}
+}
+
+// Add atomically adds delta to an AtomicUint128, and returns the new value
+//
+// This function overflows silently.
+func (a *AtomicUint128) Add(delta Uint128) (new Uint128) {
+	hi, lo := a.v.add(delta.hi, delta.lo)
+	return Uint128{hi: hi, lo: lo}
+}
+
+// CompareAndSwap atomically replaces the value of an AtomicUint128 with new, if it is equal to old
+func (a *AtomicUint128) CompareAndSwap(old, new Uint128) (swapped bool) {
+	return a.v.cas(old.hi, old.lo, new.hi, new.lo)
+}
+
+// Load atomically loads the value of an AtomicUint128
+func (a *AtomicUint128) Load() Uint128 {
+	hi, lo := a.v.load()
+	return Uint128{hi: hi, lo: lo}
+}
+
+// Store atomically stores a value into an AtomicUint128
+func (a *AtomicUint128) Store(val Uint128) {
+	a.v.swap(val.hi, val.lo)
+}
+
+// Swap atomically stores new into an AtomicUint128, and returns the old value
+func (a *AtomicUint128) Swap(new Uint128) (old Uint128) {
+	hi, lo := a.v.swap(new.hi, new.lo)
+	return Uint128{hi: hi, lo: lo}
+}
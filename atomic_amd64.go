@@ -0,0 +1,107 @@
+//go:build amd64 && !purego && !race
+
+package wide
+
+import (
+	"math/bits"
+	"sync"
+	"unsafe"
+)
+
+// atomic128 is the storage of a 128-bit atomic, updated with CMPXCHG16B
+//
+// CMPXCHG16B requires its operand to be 16-byte aligned, which Go does not guarantee, so the value is kept in whichever 16-byte
+// aligned pair of words lies within v. The lower word comes first.
+type atomic128 struct {
+	v [3]uint64
+}
+
+// hasCMPXCHG16B reports whether the CPU supports CMPXCHG16B, which a few of the earliest amd64 CPUs do not
+var hasCMPXCHG16B = cpuHasCMPXCHG16B()
+
+// atomicLocks serialize access to 128-bit atomics when CMPXCHG16B is not supported, striped by address
+var atomicLocks [64]struct {
+	sync.Mutex
+	_ [56]byte // pad to a cache line
+}
+
+// cpuHasCMPXCHG16B reports whether CPUID advertises CMPXCHG16B
+//
+//go:noescape
+func cpuHasCMPXCHG16B() bool
+
+// cas128 compares the 16 bytes at addr with old, and replaces them with new if they are equal
+//
+//go:noescape
+func cas128(addr *[2]uint64, oldLo, oldHi, newLo, newHi uint64) (swapped bool)
+
+// load128 atomically loads the 16 bytes at addr
+//
+//go:noescape
+func load128(addr *[2]uint64) (lo, hi uint64)
+
+// lock returns the striped lock which guards a 128-bit atomic when CMPXCHG16B is not supported
+func (a *atomic128) lock() *sync.Mutex {
+	return &atomicLocks[uintptr(unsafe.Pointer(a))/8%uintptr(len(atomicLocks))].Mutex
+}
+
+// pair returns the 16-byte aligned pair of words which holds the value
+func (a *atomic128) pair() *[2]uint64 {
+	p := unsafe.Pointer(&a.v)
+	if uintptr(p)%16 != 0 {
+		p = unsafe.Add(p, 8)
+	}
+	return (*[2]uint64)(p)
+}
+
+// add atomically adds (hi, lo) to the value, and returns the new value
+func (a *atomic128) add(hi, lo uint64) (newHi, newLo uint64) {
+	for {
+		oldHi, oldLo := a.load()
+		var carry uint64
+		newLo, carry = bits.Add64(oldLo, lo, 0)
+		newHi, _ = bits.Add64(oldHi, hi, carry)
+		if a.cas(oldHi, oldLo, newHi, newLo) {
+			return newHi, newLo
+		}
+	}
+}
+
+// cas atomically replaces the value with (newHi, newLo), if it is equal to (oldHi, oldLo)
+func (a *atomic128) cas(oldHi, oldLo, newHi, newLo uint64) bool {
+	p := a.pair()
+	if hasCMPXCHG16B {
+		return cas128(p, oldLo, oldHi, newLo, newHi)
+	}
+	mu := a.lock()
+	mu.Lock()
+	defer mu.Unlock()
+	if p[0] != oldLo || p[1] != oldHi {
+		return false
+	}
+	p[0], p[1] = newLo, newHi
+	return true
+}
+
+// load atomically loads the value
+func (a *atomic128) load() (hi, lo uint64) {
+	p := a.pair()
+	if hasCMPXCHG16B {
+		lo, hi = load128(p)
+		return hi, lo
+	}
+	mu := a.lock()
+	mu.Lock()
+	defer mu.Unlock()
+	return p[1], p[0]
+}
+
+// swap atomically replaces the value with (hi, lo), and returns the old value
+func (a *atomic128) swap(hi, lo uint64) (oldHi, oldLo uint64) {
+	for {
+		oldHi, oldLo = a.load()
+		if a.cas(oldHi, oldLo, hi, lo) {
+			return oldHi, oldLo
+		}
+	}
+}
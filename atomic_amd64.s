@@ -0,0 +1,41 @@
+//go:build amd64 && !purego && !race
+
+#include "textflag.h"
+
+// func cpuHasCMPXCHG16B() bool
+TEXT ·cpuHasCMPXCHG16B(SB), NOSPLIT, $0-1
+	MOVL $1, AX
+	XORL CX, CX
+	CPUID
+	SHRL $13, CX
+	ANDL $1, CX
+	MOVB CX, ret+0(FP)
+	RET
+
+// func cas128(addr *[2]uint64, oldLo, oldHi, newLo, newHi uint64) (swapped bool)
+TEXT ·cas128(SB), NOSPLIT, $0-41
+	MOVQ addr+0(FP), DI
+	MOVQ oldLo+8(FP), AX
+	MOVQ oldHi+16(FP), DX
+	MOVQ newLo+24(FP), BX
+	MOVQ newHi+32(FP), CX
+	LOCK
+	CMPXCHG16B (DI)
+	SETEQ swapped+40(FP)
+	RET
+
+// func load128(addr *[2]uint64) (lo, hi uint64)
+//
+// Compares the operand with 0 and replaces it with 0 if they are equal, which leaves it unchanged either way, but loads it
+// atomically into DX:AX.
+TEXT ·load128(SB), NOSPLIT, $0-24
+	MOVQ addr+0(FP), DI
+	XORQ AX, AX
+	XORQ DX, DX
+	XORQ BX, BX
+	XORQ CX, CX
+	LOCK
+	CMPXCHG16B (DI)
+	MOVQ AX, lo+8(FP)
+	MOVQ DX, hi+16(FP)
+	RET
@@ -0,0 +1,26 @@
+//go:build amd64 && !purego && !race
+
+package wide
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestAtomicUint128ConcurrentFallback(t *testing.T) {
+	defer func(has bool) { hasCMPXCHG16B = has }(hasCMPXCHG16B)
+	hasCMPXCHG16B = false
+	stressAtomicUint128(t)
+}
+
+// TestAtomicUint128ConcurrentCMPXCHG16B stress tests the assembly, which the race detector never sees since race builds use
+// the seqlock instead
+func TestAtomicUint128ConcurrentCMPXCHG16B(t *testing.T) {
+	if !hasCMPXCHG16B {
+		t.Skip("CPU does not support CMPXCHG16B")
+	}
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(max(runtime.NumCPU(), 8)))
+	for i := 0; i < 10 && !t.Failed(); i++ {
+		stressAtomicUint128(t)
+	}
+}
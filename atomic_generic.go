@@ -0,0 +1,85 @@
+//go:build !amd64 || purego || race
+
+package wide
+
+import (
+	"math/bits"
+	"runtime"
+	"sync/atomic"
+)
+
+// atomic128 is the storage of a 128-bit atomic, guarded by a seqlock
+//
+// Writers serialize on seq, which is odd while a write is in progress. Readers retry until they observe the same even seq before
+// and after reading both words. Every word is accessed atomically, so that the race detector sees the synchronization.
+type atomic128 struct {
+	seq    atomic.Uint64
+	hi, lo atomic.Uint64
+}
+
+// add atomically adds (hi, lo) to the value, and returns the new value
+func (a *atomic128) add(hi, lo uint64) (newHi, newLo uint64) {
+	a.lock()
+	defer a.unlock()
+	var carry uint64
+	newLo, carry = bits.Add64(a.lo.Load(), lo, 0)
+	newHi, _ = bits.Add64(a.hi.Load(), hi, carry)
+	a.hi.Store(newHi)
+	a.lo.Store(newLo)
+	return newHi, newLo
+}
+
+// cas atomically replaces the value with (newHi, newLo), if it is equal to (oldHi, oldLo)
+func (a *atomic128) cas(oldHi, oldLo, newHi, newLo uint64) bool {
+	if hi, lo := a.load(); hi != oldHi || lo != oldLo {
+		return false
+	}
+	a.lock()
+	defer a.unlock()
+	if a.hi.Load() != oldHi || a.lo.Load() != oldLo {
+		return false
+	}
+	a.hi.Store(newHi)
+	a.lo.Store(newLo)
+	return true
+}
+
+// load atomically loads the value
+func (a *atomic128) load() (hi, lo uint64) {
+	for {
+		seq := a.seq.Load()
+		if seq%2 == 0 {
+			hi, lo = a.hi.Load(), a.lo.Load()
+			if a.seq.Load() == seq {
+				return hi, lo
+			}
+		}
+		runtime.Gosched()
+	}
+}
+
+// lock waits until no write is in progress, and begins a write
+func (a *atomic128) lock() {
+	for {
+		seq := a.seq.Load()
+		if seq%2 == 0 && a.seq.CompareAndSwap(seq, seq+1) {
+			return
+		}
+		runtime.Gosched()
+	}
+}
+
+// swap atomically replaces the value with (hi, lo), and returns the old value
+func (a *atomic128) swap(hi, lo uint64) (oldHi, oldLo uint64) {
+	a.lock()
+	defer a.unlock()
+	oldHi, oldLo = a.hi.Load(), a.lo.Load()
+	a.hi.Store(hi)
+	a.lo.Store(lo)
+	return oldHi, oldLo
+}
+
+// unlock ends a write
+func (a *atomic128) unlock() {
+	a.seq.Add(1)
+}
@@ -0,0 +1,93 @@
+package wide
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestAtomicUint128(t *testing.T) {
+	var a AtomicUint128
+	if result := a.Load(); result != (Uint128{}) {
+		t.Errorf("Expected zero AtomicUint128 == 0x0, got: %s", result)
+	}
+	x, y := Uint128{hi: 1, lo: 1<<64 - 1}, Uint128{hi: 2, lo: 3}
+	a.Store(x)
+	if result := a.Load(); result != x {
+		t.Errorf("Expected AtomicUint128.Load() == %s, got: %s", x, result)
+	}
+	if result := a.Add(Uint128{lo: 1}); result != (Uint128{hi: 2}) {
+		t.Errorf("Expected AtomicUint128.Add(0x1) == 0x20000000000000000, got: %s", result)
+	}
+	if a.CompareAndSwap(x, y) {
+		t.Errorf("Expected AtomicUint128.CompareAndSwap(%s, %s) to fail", x, y)
+	}
+	if !a.CompareAndSwap(Uint128{hi: 2}, y) {
+		t.Errorf("Expected AtomicUint128.CompareAndSwap(0x20000000000000000, %s) to succeed", y)
+	}
+	if result := a.Swap(x); result != y {
+		t.Errorf("Expected AtomicUint128.Swap(%s) == %s, got: %s", x, y, result)
+	}
+	if result := a.Load(); result != x {
+		t.Errorf("Expected AtomicUint128.Load() == %s, got: %s", x, result)
+	}
+}
+
+func TestAtomicInt128(t *testing.T) {
+	var a AtomicInt128
+	minusOne := Int128{hi: -1, lo: 1<<64 - 1}
+	if result := a.Add(minusOne); result != minusOne {
+		t.Errorf("Expected AtomicInt128.Add(-1) == -1, got: %s", result)
+	}
+	if !a.CompareAndSwap(minusOne, Int128{lo: 7}) {
+		t.Errorf("Expected AtomicInt128.CompareAndSwap(-1, 7) to succeed")
+	}
+	if result := a.Swap(minusOne); result != (Int128{lo: 7}) {
+		t.Errorf("Expected AtomicInt128.Swap(-1) == 7, got: %s", result)
+	}
+	a.Store(Int128{hi: 1})
+	if result := a.Load(); result != (Int128{hi: 1}) {
+		t.Errorf("Expected AtomicInt128.Load() == 18446744073709551616, got: %s", result)
+	}
+}
+
+// stressAtomicUint128 hammers an AtomicUint128 from many goroutines, checking that no update is lost and no load is torn
+func stressAtomicUint128(t *testing.T) {
+	const n, m = 8, 2000
+	var sum, pair AtomicUint128
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < m; j++ {
+				// Adding 2^63 carries into the upper half on every other call
+				sum.Add(Uint128{lo: 1 << 63})
+				for {
+					old := sum.Load()
+					if sum.CompareAndSwap(old, old.Add(Uint128{hi: 1})) {
+						break
+					}
+				}
+				// Both halves of pair are always equal, unless a load is torn
+				v := uint64(i*m + j)
+				if j%2 == 0 {
+					pair.Store(Uint128{hi: v, lo: v})
+				} else {
+					pair.Swap(Uint128{hi: v, lo: v})
+				}
+				if p := pair.Load(); p.hi != p.lo {
+					t.Errorf("Expected AtomicUint128.Load() to be consistent, got: %s", p)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	if result, expected := sum.Load(), (Uint128{hi: n * m * 3 / 2}); result != expected {
+		t.Errorf("Expected AtomicUint128 == %s after concurrent updates, got: %s", expected, result)
+	}
+}
+
+func TestAtomicUint128Concurrent(t *testing.T) {
+	stressAtomicUint128(t)
+}
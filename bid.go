@@ -0,0 +1,148 @@
+package wide
+
+// BIDSpecial classifies an IEEE 754-2008 decimal128 value, see DecodeBID128 and EncodeBID128
+type BIDSpecial byte
+
+// Decimal128 classes
+//
+// BIDNegative may be combined with any class, and is the only way to express the sign of zeros, infinities and NaNs.
+const (
+	BIDFinite       BIDSpecial = iota // finite number, including zero
+	BIDInfinity                       // infinity
+	BIDQuietNaN                       // quiet NaN
+	BIDSignalingNaN                   // signaling NaN
+
+	BIDNegative BIDSpecial = 0x80 // sign bit
+)
+
+// Decimal128 parameters
+const (
+	bidBias        = 6176
+	bidMaxExponent = 6111  // largest exponent of the coefficient
+	bidMinExponent = -6176 // smallest exponent of the coefficient
+	bidDigits      = 34    // precision in decimal digits
+)
+
+// bidMaxCoef is the largest canonical decimal128 coefficient, i.e. 10^34 - 1
+var bidMaxCoef = pow10tab[bidDigits].Dec()
+
+// bidMaxPayload is the largest canonical decimal128 NaN payload, i.e. 10^33 - 1
+var bidMaxPayload = pow10tab[bidDigits-1].Dec()
+
+// DecodeBID128 decodes a decimal128 value in the binary integer decimal (BID) encoding, given in big-endian byte order
+//
+// Finite values decode to coef * 10^exp, with the sign in both coef and special (so that -0 is not lost). For NaNs, coef holds the
+// payload. Non-canonical coefficients and payloads decode as 0, as required by IEEE 754-2008. Little-endian data (e.g. BSON)
+// must be reversed first.
+func DecodeBID128(b [16]byte) (coef Int128, exp int, special BIDSpecial) {
+	var x Uint128
+	for _, c := range b {
+		x = x.LShiftN(8)
+		x.lo |= uint64(c)
+	}
+	neg := x.hi>>63 != 0
+	if neg {
+		special = BIDNegative
+	}
+	var mag Uint128
+	switch g := x.hi >> 58 & 0x1f; {
+	case g == 0x1f:
+		special |= BIDQuietNaN
+		if x.hi>>57&1 != 0 {
+			special = special&BIDNegative | BIDSignalingNaN
+		}
+		if mag = (Uint128{hi: x.hi & (1<<46 - 1), lo: x.lo}); mag.Gt(bidMaxPayload) {
+			mag = Uint128{}
+		}
+		return mag.Int128(), 0, special
+	case g == 0x1e:
+		return Int128{}, 0, special | BIDInfinity
+	case g>>3 == 3:
+		// The implied coefficient is at least 2^113, which is never canonical
+		exp = int(x.hi>>47&(1<<14-1)) - bidBias
+	default:
+		exp = int(x.hi>>49&(1<<14-1)) - bidBias
+		if mag = (Uint128{hi: x.hi & (1<<49 - 1), lo: x.lo}); mag.Gt(bidMaxCoef) {
+			mag = Uint128{}
+		}
+	}
+	return signed128(mag, neg), exp, special
+}
+
+// EncodeBID128 encodes a decimal128 value in the binary integer decimal (BID) encoding, returned in big-endian byte order
+//
+// Finite values are coef * 10^exp, and are negative if either coef is negative or special includes BIDNegative. Values which are
+// not representable exactly are rounded (ties to even) to 34 digits, clamped, and overflow to infinity or underflow to zero, as in
+// IEEE 754-2008. For NaNs, coef holds the payload, which becomes 0 if it is not canonical.
+func EncodeBID128(coef Int128, exp int, special BIDSpecial) (b [16]byte) {
+	neg := special&BIDNegative != 0 || coef.IsNeg()
+	mag := coef.Abs().Uint128()
+	var x Uint128
+	switch special &^ BIDNegative {
+	case BIDInfinity:
+		x.hi = 0x1e << 58
+	case BIDQuietNaN, BIDSignalingNaN:
+		x.hi = 0x1f << 58
+		if special&^BIDNegative == BIDSignalingNaN {
+			x.hi |= 1 << 57
+		}
+		if mag.Lte(bidMaxPayload) {
+			x = x.Or(mag)
+		}
+	default:
+		mag, exp = bidRound(mag, exp, neg)
+		switch {
+		case mag.Gt(bidMaxCoef) || exp > bidMaxExponent:
+			x.hi = 0x1e << 58
+		default:
+			x = mag
+			x.hi |= uint64(exp+bidBias) << 49
+		}
+	}
+	if neg {
+		x.hi |= 1 << 63
+	}
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i] = byte(x.lo)
+		x = x.RShiftN(8)
+	}
+	return b
+}
+
+// bidRound returns the magnitude and exponent of a finite decimal128, rounded to 34 digits and to the exponent range
+//
+// If the result overflows, the returned exponent exceeds bidMaxExponent.
+func bidRound(mag Uint128, exp int, neg bool) (Uint128, int) {
+	if mag.hi == 0 && mag.lo == 0 {
+		// Zeros are clamped to the exponent range, without any change in value
+		return mag, min(max(exp, bidMinExponent), bidMaxExponent)
+	}
+	// Drop excess digits, and digits below the smallest exponent
+	var k uint
+	if d := mag.Log10() + 1; d > bidDigits {
+		k = d - bidDigits
+	}
+	if exp+int(k) < bidMinExponent {
+		k = uint(bidMinExponent - exp)
+	}
+	if k > 0 {
+		if k > 2*MaxDecimalScale {
+			// Everything is discarded, but the rounding direction still depends on whether anything was non-zero
+			k = 2 * MaxDecimalScale
+		}
+		_, mag = divRound256(Uint128{}, mag, Uint128{lo: 1}, k, neg, RoundHalfEven)
+		exp += int(k)
+		if mag.Gt(bidMaxCoef) {
+			// Rounding carried into another digit
+			mag = mag.Div(pow10tab[1])
+			exp++
+		}
+		exp = max(exp, bidMinExponent)
+	}
+	// Clamp large exponents by padding the coefficient with zeros, if there is room
+	for exp > bidMaxExponent && mag.Mul(pow10tab[1]).Lte(bidMaxCoef) {
+		mag = mag.Mul(pow10tab[1])
+		exp--
+	}
+	return mag, exp
+}
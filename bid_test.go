@@ -0,0 +1,117 @@
+package wide
+
+import (
+	"encoding/hex"
+	"math/rand"
+	"testing"
+)
+
+// bid128 returns the 16 bytes represented by a 32-digit hexadecimal string
+func bid128(s string) (b [16]byte) {
+	if n, err := hex.Decode(b[:], []byte(s)); err != nil || n != len(b) {
+		panic("invalid decimal128 hex string " + s)
+	}
+	return b
+}
+
+// bidVectors are decimal128 encodings, in the big-endian hexadecimal notation used by conformance test suites
+var bidVectors = []struct {
+	bits    string
+	coef    Int128
+	exp     int
+	special BIDSpecial
+}{
+	{"30400000000000000000000000000001", Int128{hi: 0, lo: 1}, 0, BIDFinite},
+	{"B0400000000000000000000000000001", Int128FromInt64(-1), 0, BIDNegative},
+	{"30400000000000000000000000000000", Int128{hi: 0, lo: 0}, 0, BIDFinite},
+	{"B0400000000000000000000000000000", Int128{hi: 0, lo: 0}, 0, BIDNegative},
+	{"303E0000000000000000000000000001", Int128{hi: 0, lo: 1}, -1, BIDFinite},                                  // 0.1
+	{"B03C0000000000000000000000003039", Int128FromInt64(-12345), -2, BIDNegative},                             // -123.45
+	{"5FFFED09BEAD87C0378D8E63FFFFFFFF", Int128{hi: 0x1ed09bead87c0, lo: 0x378d8e63ffffffff}, 6111, BIDFinite}, // largest finite
+	{"0001ED09BEAD87C0378D8E63FFFFFFFF", Int128{hi: 0x1ed09bead87c0, lo: 0x378d8e63ffffffff}, -6176, BIDFinite},
+	{"00000000000000000000000000000001", Int128{hi: 0, lo: 1}, -6176, BIDFinite}, // smallest subnormal
+	{"5FFE0000000000000000000000000000", Int128{hi: 0, lo: 0}, 6111, BIDFinite},
+	{"78000000000000000000000000000000", Int128{}, 0, BIDInfinity},
+	{"F8000000000000000000000000000000", Int128{}, 0, BIDInfinity | BIDNegative},
+	{"7C000000000000000000000000000000", Int128{}, 0, BIDQuietNaN},
+	{"FC000000000000000000000000000000", Int128{}, 0, BIDQuietNaN | BIDNegative},
+	{"7E000000000000000000000000000000", Int128{}, 0, BIDSignalingNaN},
+	{"7C00000000000000000000000000007B", Int128{hi: 0, lo: 123}, 0, BIDQuietNaN}, // NaN with a payload
+}
+
+func TestDecodeBID128(t *testing.T) {
+	tests := append([]struct {
+		bits    string
+		coef    Int128
+		exp     int
+		special BIDSpecial
+	}{
+		// Non-canonical encodings
+		{"3041ED09BEAD87C0378D8E6400000000", Int128{}, 0, BIDFinite},       // coefficient of 10^34
+		{"6C100000000000000000000000000000", Int128{}, 0, BIDFinite},       // implied coefficient of at least 2^113
+		{"7C003FFFFFFFFFFFFFFFFFFFFFFFFFFF", Int128{}, 0, BIDQuietNaN},     // payload of at least 10^33
+		{"7A000000000000000000000000000000", Int128{}, 0, BIDInfinity},     // infinity with non-zero trailing bits
+		{"7FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF", Int128{}, 0, BIDSignalingNaN}, // signaling NaN with all bits set
+		{"F87FFFFFFFFFFFFFFFFFFFFFFFFFFFFF", Int128{}, 0, BIDInfinity | BIDNegative},
+	}, bidVectors...)
+	for _, test := range tests {
+		coef, exp, special := DecodeBID128(bid128(test.bits))
+		if !coef.Eq(test.coef) || exp != test.exp || special != test.special {
+			t.Errorf("Expected DecodeBID128(%s) == %s, %v, %#x got: %s, %v, %#x", test.bits, test.coef, test.exp, test.special, coef, exp, special)
+		}
+	}
+}
+
+func TestEncodeBID128(t *testing.T) {
+	tests := []struct {
+		coef    Int128
+		exp     int
+		special BIDSpecial
+		bits    string
+	}{
+		// Rounding to 34 digits, with ties to even
+		{Int128{hi: 0x260b05ffbe7fc, lo: 0xb117a024f1e2df79}, 0, BIDFinite, "30423CDE6FFF9732DE825CD07E96AFF2"},
+		{Int128FromInt64(15), -6177, BIDFinite, "00000000000000000000000000000002"},
+		{Int128FromInt64(25), -6177, BIDFinite, "00000000000000000000000000000002"},
+		{Int128FromInt64(-26), -6177, BIDFinite, "80000000000000000000000000000003"},
+		{Int128FromInt64(5), -6200, BIDFinite, "00000000000000000000000000000000"},
+		// Clamping and overflow
+		{Int128FromInt64(1), 6120, BIDFinite, "5FFE000000000000000000003B9ACA00"},
+		{Int128{hi: 0x1ed09bead87c0, lo: 0x378d8e63ffffffff}, 6112, BIDFinite, "78000000000000000000000000000000"},
+		{Int128{hi: 0, lo: 0}, 10000, BIDNegative, "DFFE0000000000000000000000000000"},
+		{Int128{hi: 0, lo: 0}, -10000, BIDFinite, "00000000000000000000000000000000"},
+		// Non-canonical NaN payloads
+		{Int128{hi: 0x314dc6448d93, lo: 0x38c15b0a00000000}, 0, BIDSignalingNaN, "7E000000000000000000000000000000"},
+	}
+	for _, test := range tests {
+		b := EncodeBID128(test.coef, test.exp, test.special)
+		if expected := bid128(test.bits); b != expected {
+			t.Errorf("Expected EncodeBID128(%s, %v, %#x) == %s, got: %X", test.coef, test.exp, test.special, test.bits, b)
+		}
+	}
+	for _, test := range bidVectors {
+		b := EncodeBID128(test.coef, test.exp, test.special)
+		if expected := bid128(test.bits); b != expected {
+			t.Errorf("Expected EncodeBID128(%s, %v, %#x) == %s, got: %X", test.coef, test.exp, test.special, test.bits, b)
+		}
+	}
+}
+
+func TestRoundTripBID128(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		coef := randNumTheoryInt128(r).RShiftN(uint(r.Intn(128)))
+		for coef.Abs().Uint128().Gt(bidMaxCoef) {
+			coef = coef.RShiftN(1)
+		}
+		exp := r.Intn(bidMaxExponent-bidMinExponent+1) + bidMinExponent
+		special := BIDFinite
+		if coef.IsNeg() {
+			special = BIDNegative
+		}
+		coef2, exp2, special2 := DecodeBID128(EncodeBID128(coef, exp, special))
+		if !coef2.Eq(coef) || exp2 != exp || special2 != special {
+			t.Errorf("Expected DecodeBID128(EncodeBID128(%s, %v)) == %s, %v, got: %s, %v", coef, exp, coef, exp, coef2, exp2)
+		}
+	}
+}
@@ -0,0 +1,590 @@
+package wide
+
+import (
+	"encoding/binary"
+	"errors"
+	"iter"
+	"math/bits"
+	"slices"
+	"sort"
+)
+
+const (
+	// arrayMaxLen is the cardinality above which an array container is larger than a bitmap container
+	arrayMaxLen = 4096
+	// bitmapWords is the number of words in a bitmap container, one bit for each of the 2^16 values
+	bitmapWords = 1 << 16 / 64
+)
+
+// Container kinds, as serialized by Bitmap128.MarshalBinary
+const (
+	kindArray byte = iota
+	kindBitmap
+	kindRun
+)
+
+// Operations between containers
+const (
+	opAnd = iota
+	opAndNot
+	opOr
+	opXor
+)
+
+// Bitmap128 is a compressed set of Uint128's, in the style of Roaring bitmaps
+//
+// Values are partitioned by their upper 112 bits into containers of up to 2^16 values. A container is a sorted array while it
+// holds at most 4096 values, and a bitmap otherwise. RunOptimize converts containers to sorted runs of consecutive values where
+// that is smaller, which Add and Remove expand again when they change a run container. The zero value is an empty set.
+type Bitmap128 struct {
+	keys       []Uint128 // the upper 112 bits of the values in each container, in ascending order
+	containers []container
+}
+
+// container is a set of the lower 16 bits of the values in a Bitmap128 which share their upper 112 bits
+//
+// The methods which modify a container return the container which replaces it, which may be of another kind.
+type container interface {
+	add(x uint16) container
+	cardinality() int
+	clone() container
+	contains(x uint16) bool
+	iterate(yield func(uint16) bool) bool
+	remove(x uint16) container
+	toBitmap() *bitmapContainer
+}
+
+// arrayContainer is a container of at most arrayMaxLen values, in ascending order
+type arrayContainer struct {
+	vals []uint16
+}
+
+// bitmapContainer is a container with one bit for each of the 2^16 values, and its cardinality
+type bitmapContainer struct {
+	words [bitmapWords]uint64
+	n     int
+}
+
+// runContainer is a container of disjoint and non-adjacent runs of consecutive values, in ascending order
+type runContainer struct {
+	runs []run16
+}
+
+// run16 is the closed range [start, last] of a runContainer
+type run16 struct {
+	start, last uint16
+}
+
+// errBitmapEncoding is returned when unmarshaling an invalid Bitmap128 encoding
+var errBitmapEncoding = errors.New("wide: invalid Bitmap128 encoding")
+
+// Add adds x to a Bitmap128
+func (s *Bitmap128) Add(x Uint128) {
+	key, low := x.RShiftN(16), uint16(x.lo)
+	i, found := slices.BinarySearchFunc(s.keys, key, Uint128.Cmp)
+	if !found {
+		s.keys = slices.Insert(s.keys, i, key)
+		s.containers = slices.Insert(s.containers, i, container(&arrayContainer{}))
+	}
+	s.containers[i] = s.containers[i].add(low)
+}
+
+// All returns an iterator over the values of a Bitmap128, in ascending order
+func (s *Bitmap128) All() iter.Seq[Uint128] {
+	return func(yield func(Uint128) bool) {
+		for i, c := range s.containers {
+			base := s.keys[i].LShiftN(16)
+			if !c.iterate(func(x uint16) bool { return yield(Uint128{hi: base.hi, lo: base.lo | uint64(x)}) }) {
+				return
+			}
+		}
+	}
+}
+
+// And returns the intersection of two Bitmap128's
+func (s *Bitmap128) And(t *Bitmap128) *Bitmap128 {
+	return s.combine(t, opAnd)
+}
+
+// AndNot returns the values of s which are not in t
+func (s *Bitmap128) AndNot(t *Bitmap128) *Bitmap128 {
+	return s.combine(t, opAndNot)
+}
+
+// Cardinality returns the number of values in a Bitmap128
+func (s *Bitmap128) Cardinality() (n Uint128) {
+	for _, c := range s.containers {
+		n = n.Add(Uint128FromUint64(uint64(c.cardinality())))
+	}
+	return n
+}
+
+// combine returns the result of a set operation between two Bitmap128's, without modifying either
+func (s *Bitmap128) combine(t *Bitmap128, op int) *Bitmap128 {
+	z := new(Bitmap128)
+	i, j := 0, 0
+	for i < len(s.keys) || j < len(t.keys) {
+		switch {
+		case j == len(t.keys) || i < len(s.keys) && s.keys[i].Lt(t.keys[j]):
+			if op != opAnd {
+				z.keys, z.containers = append(z.keys, s.keys[i]), append(z.containers, s.containers[i].clone())
+			}
+			i++
+		case i == len(s.keys) || t.keys[j].Lt(s.keys[i]):
+			if op == opOr || op == opXor {
+				z.keys, z.containers = append(z.keys, t.keys[j]), append(z.containers, t.containers[j].clone())
+			}
+			j++
+		default:
+			if c := combineContainers(s.containers[i], t.containers[j], op); c != nil {
+				z.keys, z.containers = append(z.keys, s.keys[i]), append(z.containers, c)
+			}
+			i++
+			j++
+		}
+	}
+	return z
+}
+
+// Contains returns whether x is in a Bitmap128
+func (s *Bitmap128) Contains(x Uint128) bool {
+	i, found := slices.BinarySearchFunc(s.keys, x.RShiftN(16), Uint128.Cmp)
+	return found && s.containers[i].contains(uint16(x.lo))
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface
+//
+// The encoding is the number of containers as a uvarint, followed by each container's upper 112 bits as 16 big-endian bytes,
+// its kind, and its contents. An array is its length as a uvarint followed by its values, a bitmap is its 1024 words, and a
+// run container is its number of runs as a uvarint followed by the first and last value of each run. Values and words are
+// little-endian.
+func (s *Bitmap128) MarshalBinary() ([]byte, error) {
+	buf := binary.AppendUvarint(nil, uint64(len(s.keys)))
+	for i, c := range s.containers {
+		buf = binary.BigEndian.AppendUint64(buf, s.keys[i].hi)
+		buf = binary.BigEndian.AppendUint64(buf, s.keys[i].lo)
+		switch c := c.(type) {
+		case *arrayContainer:
+			buf = append(buf, kindArray)
+			buf = binary.AppendUvarint(buf, uint64(len(c.vals)))
+			for _, x := range c.vals {
+				buf = binary.LittleEndian.AppendUint16(buf, x)
+			}
+		case *bitmapContainer:
+			buf = append(buf, kindBitmap)
+			for _, w := range c.words {
+				buf = binary.LittleEndian.AppendUint64(buf, w)
+			}
+		case *runContainer:
+			buf = append(buf, kindRun)
+			buf = binary.AppendUvarint(buf, uint64(len(c.runs)))
+			for _, r := range c.runs {
+				buf = binary.LittleEndian.AppendUint16(buf, r.start)
+				buf = binary.LittleEndian.AppendUint16(buf, r.last)
+			}
+		}
+	}
+	return buf, nil
+}
+
+// Or returns the union of two Bitmap128's
+func (s *Bitmap128) Or(t *Bitmap128) *Bitmap128 {
+	return s.combine(t, opOr)
+}
+
+// Remove removes x from a Bitmap128
+func (s *Bitmap128) Remove(x Uint128) {
+	i, found := slices.BinarySearchFunc(s.keys, x.RShiftN(16), Uint128.Cmp)
+	if !found {
+		return
+	}
+	if c := s.containers[i].remove(uint16(x.lo)); c != nil && c.cardinality() > 0 {
+		s.containers[i] = c
+		return
+	}
+	s.keys, s.containers = slices.Delete(s.keys, i, i+1), slices.Delete(s.containers, i, i+1)
+}
+
+// RunOptimize converts each container of a Bitmap128 to whichever of an array, bitmap or run container is smallest
+func (s *Bitmap128) RunOptimize() {
+	for i, c := range s.containers {
+		var runs []run16
+		c.iterate(func(x uint16) bool {
+			if n := len(runs); n > 0 && runs[n-1].last+1 == x {
+				runs[n-1].last = x
+			} else {
+				runs = append(runs, run16{x, x})
+			}
+			return true
+		})
+		// The sizes in bytes of a run container, and of an array or bitmap container
+		runSize, size := 4*len(runs), 2*c.cardinality()
+		if c.cardinality() > arrayMaxLen {
+			size = 8 * bitmapWords
+		}
+		switch _, isRun := c.(*runContainer); {
+		case runSize < size:
+			s.containers[i] = &runContainer{runs: runs}
+		case isRun:
+			s.containers[i] = c.toBitmap().shrink()
+		}
+	}
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface, see MarshalBinary
+func (s *Bitmap128) UnmarshalBinary(data []byte) error {
+	n, data, ok := readUvarint(data)
+	if !ok || n > uint64(len(data))/17 {
+		return errBitmapEncoding
+	}
+	keys, containers := make([]Uint128, 0, n), make([]container, 0, n)
+	for ; n > 0; n-- {
+		if len(data) < 17 {
+			return errBitmapEncoding
+		}
+		key := Uint128{hi: binary.BigEndian.Uint64(data), lo: binary.BigEndian.Uint64(data[8:])}
+		kind := data[16]
+		data = data[17:]
+		if key.hi>>48 != 0 || len(keys) > 0 && !keys[len(keys)-1].Lt(key) {
+			return errBitmapEncoding
+		}
+		var c container
+		switch kind {
+		case kindArray:
+			var m uint64
+			if m, data, ok = readUvarint(data); !ok || m == 0 || m > arrayMaxLen || uint64(len(data)) < 2*m {
+				return errBitmapEncoding
+			}
+			a := &arrayContainer{vals: make([]uint16, m)}
+			for i := range a.vals {
+				a.vals[i] = binary.LittleEndian.Uint16(data[2*i:])
+				if i > 0 && a.vals[i] <= a.vals[i-1] {
+					return errBitmapEncoding
+				}
+			}
+			data = data[2*m:]
+			c = a
+		case kindBitmap:
+			if len(data) < 8*bitmapWords {
+				return errBitmapEncoding
+			}
+			b := new(bitmapContainer)
+			for i := range b.words {
+				b.words[i] = binary.LittleEndian.Uint64(data[8*i:])
+				b.n += bits.OnesCount64(b.words[i])
+			}
+			data = data[8*bitmapWords:]
+			if b.n == 0 {
+				return errBitmapEncoding
+			}
+			c = b
+		case kindRun:
+			var m uint64
+			if m, data, ok = readUvarint(data); !ok || m == 0 || m > 1<<15 || uint64(len(data)) < 4*m {
+				return errBitmapEncoding
+			}
+			r := &runContainer{runs: make([]run16, m)}
+			for i := range r.runs {
+				r.runs[i] = run16{binary.LittleEndian.Uint16(data[4*i:]), binary.LittleEndian.Uint16(data[4*i+2:])}
+				if r.runs[i].start > r.runs[i].last || i > 0 && int(r.runs[i].start) <= int(r.runs[i-1].last)+1 {
+					return errBitmapEncoding
+				}
+			}
+			data = data[4*m:]
+			c = r
+		default:
+			return errBitmapEncoding
+		}
+		keys, containers = append(keys, key), append(containers, c)
+	}
+	if len(data) != 0 {
+		return errBitmapEncoding
+	}
+	s.keys, s.containers = keys, containers
+	return nil
+}
+
+// Xor returns the symmetric difference of two Bitmap128's
+func (s *Bitmap128) Xor(t *Bitmap128) *Bitmap128 {
+	return s.combine(t, opXor)
+}
+
+// combineContainers returns the result of a set operation between two containers, or nil if it is empty
+//
+// Neither container is modified, and the result does not alias either of them.
+func combineContainers(a, b container, op int) container {
+	x, aIsArray := a.(*arrayContainer)
+	y, bIsArray := b.(*arrayContainer)
+	switch {
+	case op == opAnd && aIsArray:
+		return filterArray(x, b, true)
+	case op == opAnd && bIsArray:
+		return filterArray(y, a, true)
+	case op == opAndNot && aIsArray:
+		return filterArray(x, b, false)
+	case (op == opOr || op == opXor) && aIsArray && bIsArray:
+		vals := make([]uint16, 0, len(x.vals)+len(y.vals))
+		i, j := 0, 0
+		for i < len(x.vals) || j < len(y.vals) {
+			switch {
+			case j == len(y.vals) || i < len(x.vals) && x.vals[i] < y.vals[j]:
+				vals = append(vals, x.vals[i])
+				i++
+			case i == len(x.vals) || y.vals[j] < x.vals[i]:
+				vals = append(vals, y.vals[j])
+				j++
+			default:
+				if op == opOr {
+					vals = append(vals, x.vals[i])
+				}
+				i++
+				j++
+			}
+		}
+		if len(vals) > arrayMaxLen {
+			return (&arrayContainer{vals: vals}).toBitmap()
+		}
+		if len(vals) == 0 {
+			return nil
+		}
+		return &arrayContainer{vals: vals}
+	}
+	z, w := a.toBitmap(), b.toBitmap()
+	z.n = 0
+	for i := range z.words {
+		switch op {
+		case opAnd:
+			z.words[i] &= w.words[i]
+		case opAndNot:
+			z.words[i] &^= w.words[i]
+		case opOr:
+			z.words[i] |= w.words[i]
+		case opXor:
+			z.words[i] ^= w.words[i]
+		}
+		z.n += bits.OnesCount64(z.words[i])
+	}
+	return z.shrink()
+}
+
+// filterArray returns the values of an array container which are in another container, or which are not if keep is false,
+// or nil if there are none
+func filterArray(a *arrayContainer, c container, keep bool) container {
+	var vals []uint16
+	for _, x := range a.vals {
+		if c.contains(x) == keep {
+			vals = append(vals, x)
+		}
+	}
+	if len(vals) == 0 {
+		return nil
+	}
+	return &arrayContainer{vals: vals}
+}
+
+// readUvarint reads a uvarint from the start of data, and returns the rest of data
+func readUvarint(data []byte) (uint64, []byte, bool) {
+	x, n := binary.Uvarint(data)
+	if n <= 0 {
+		return 0, data, false
+	}
+	return x, data[n:], true
+}
+
+// add adds x to an array container, and converts it to a bitmap container once it holds more than arrayMaxLen values
+func (a *arrayContainer) add(x uint16) container {
+	i, found := slices.BinarySearch(a.vals, x)
+	if found {
+		return a
+	}
+	if len(a.vals) == arrayMaxLen {
+		return a.toBitmap().add(x)
+	}
+	a.vals = slices.Insert(a.vals, i, x)
+	return a
+}
+
+// cardinality returns the number of values in an array container
+func (a *arrayContainer) cardinality() int {
+	return len(a.vals)
+}
+
+// clone returns a copy of an array container
+func (a *arrayContainer) clone() container {
+	return &arrayContainer{vals: slices.Clone(a.vals)}
+}
+
+// contains returns whether x is in an array container
+func (a *arrayContainer) contains(x uint16) bool {
+	_, found := slices.BinarySearch(a.vals, x)
+	return found
+}
+
+// iterate calls yield for the values of an array container in ascending order, until it returns false
+func (a *arrayContainer) iterate(yield func(uint16) bool) bool {
+	for _, x := range a.vals {
+		if !yield(x) {
+			return false
+		}
+	}
+	return true
+}
+
+// remove removes x from an array container
+func (a *arrayContainer) remove(x uint16) container {
+	if i, found := slices.BinarySearch(a.vals, x); found {
+		a.vals = slices.Delete(a.vals, i, i+1)
+	}
+	return a
+}
+
+// toBitmap returns an array container as a new bitmap container
+func (a *arrayContainer) toBitmap() *bitmapContainer {
+	b := &bitmapContainer{n: len(a.vals)}
+	for _, x := range a.vals {
+		b.words[x/64] |= 1 << (x % 64)
+	}
+	return b
+}
+
+// add adds x to a bitmap container
+func (b *bitmapContainer) add(x uint16) container {
+	if w := &b.words[x/64]; *w&(1<<(x%64)) == 0 {
+		*w |= 1 << (x % 64)
+		b.n++
+	}
+	return b
+}
+
+// cardinality returns the number of values in a bitmap container
+func (b *bitmapContainer) cardinality() int {
+	return b.n
+}
+
+// clone returns a copy of a bitmap container
+func (b *bitmapContainer) clone() container {
+	return b.toBitmap()
+}
+
+// contains returns whether x is in a bitmap container
+func (b *bitmapContainer) contains(x uint16) bool {
+	return b.words[x/64]&(1<<(x%64)) != 0
+}
+
+// iterate calls yield for the values of a bitmap container in ascending order, until it returns false
+func (b *bitmapContainer) iterate(yield func(uint16) bool) bool {
+	for i, w := range b.words {
+		for w != 0 {
+			if !yield(uint16(i*64 + bits.TrailingZeros64(w))) {
+				return false
+			}
+			w &= w - 1
+		}
+	}
+	return true
+}
+
+// remove removes x from a bitmap container, and converts it to an array container once it holds at most arrayMaxLen values
+func (b *bitmapContainer) remove(x uint16) container {
+	if w := &b.words[x/64]; *w&(1<<(x%64)) != 0 {
+		*w &^= 1 << (x % 64)
+		b.n--
+	}
+	if b.n <= arrayMaxLen {
+		return b.shrink()
+	}
+	return b
+}
+
+// shrink returns a bitmap container as an array container if it holds at most arrayMaxLen values, or nil if it is empty
+func (b *bitmapContainer) shrink() container {
+	switch {
+	case b.n == 0:
+		return nil
+	case b.n > arrayMaxLen:
+		return b
+	}
+	a := &arrayContainer{vals: make([]uint16, 0, b.n)}
+	b.iterate(func(x uint16) bool {
+		a.vals = append(a.vals, x)
+		return true
+	})
+	return a
+}
+
+// toBitmap returns a copy of a bitmap container
+func (b *bitmapContainer) toBitmap() *bitmapContainer {
+	c := *b
+	return &c
+}
+
+// add adds x to a run container, converting it to an array or bitmap container if x is not already in it
+func (r *runContainer) add(x uint16) container {
+	if r.contains(x) {
+		return r
+	}
+	return r.toBitmap().add(x).(*bitmapContainer).shrink()
+}
+
+// cardinality returns the number of values in a run container
+func (r *runContainer) cardinality() (n int) {
+	for _, run := range r.runs {
+		n += int(run.last-run.start) + 1
+	}
+	return n
+}
+
+// clone returns a copy of a run container
+func (r *runContainer) clone() container {
+	return &runContainer{runs: slices.Clone(r.runs)}
+}
+
+// contains returns whether x is in a run container
+func (r *runContainer) contains(x uint16) bool {
+	i := sort.Search(len(r.runs), func(i int) bool { return r.runs[i].last >= x })
+	return i < len(r.runs) && r.runs[i].start <= x
+}
+
+// iterate calls yield for the values of a run container in ascending order, until it returns false
+func (r *runContainer) iterate(yield func(uint16) bool) bool {
+	for _, run := range r.runs {
+		for x := int(run.start); x <= int(run.last); x++ {
+			if !yield(uint16(x)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// remove removes x from a run container, converting it to an array or bitmap container if x is in it
+func (r *runContainer) remove(x uint16) container {
+	if !r.contains(x) {
+		return r
+	}
+	b := r.toBitmap()
+	b.remove(x)
+	if c := b.shrink(); c != nil {
+		return c
+	}
+	return &arrayContainer{}
+}
+
+// toBitmap returns a run container as a new bitmap container
+func (r *runContainer) toBitmap() *bitmapContainer {
+	b := new(bitmapContainer)
+	for _, run := range r.runs {
+		for x := int(run.start); x <= int(run.last); {
+			// Fill whole words at once where possible
+			if x%64 == 0 && x+63 <= int(run.last) {
+				b.words[x/64] = maxUint64
+				x += 64
+				continue
+			}
+			b.words[x/64] |= 1 << (x % 64)
+			x++
+		}
+		b.n += int(run.last-run.start) + 1
+	}
+	return b
+}
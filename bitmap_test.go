@@ -0,0 +1,157 @@
+package wide
+
+import (
+	"encoding"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+var (
+	_ encoding.BinaryMarshaler   = (*Bitmap128)(nil)
+	_ encoding.BinaryUnmarshaler = (*Bitmap128)(nil)
+)
+
+// randBitmapValue returns a pseudo-random Uint128 from a few dense and sparse clusters, so that every kind of container occurs
+func randBitmapValue(r *rand.Rand) Uint128 {
+	switch r.IntN(4) {
+	case 0:
+		// Dense, as a bitmap container
+		return Uint128{hi: 1, lo: r.Uint64N(1 << 14)}
+	case 1:
+		// Consecutive, as a run container
+		return Uint128{hi: 2, lo: 1<<16 - 1000 + r.Uint64N(2000)}
+	case 2:
+		// Sparse, as array containers
+		return Uint128{hi: r.Uint64N(4) << 48, lo: r.Uint64N(1 << 20)}
+	default:
+		return NewUint128(r.Uint64(), r.Uint64())
+	}
+}
+
+// checkBitmap compares a Bitmap128 to a map model
+func checkBitmap(t *testing.T, s *Bitmap128, model map[Uint128]bool) {
+	t.Helper()
+	expected := make([]Uint128, 0, len(model))
+	for x := range model {
+		expected = append(expected, x)
+	}
+	slices.SortFunc(expected, Uint128.Cmp)
+	if got := slices.Collect(s.All()); !slices.Equal(got, expected) {
+		t.Fatalf("Expected Bitmap128.All() to yield %d values in ascending order, got: %d", len(expected), len(got))
+	}
+	if n := s.Cardinality(); n != Uint128FromUint64(uint64(len(model))) {
+		t.Fatalf("Expected Bitmap128.Cardinality() == %d, got: %s", len(model), n)
+	}
+	for _, c := range s.containers {
+		if c.cardinality() == 0 {
+			t.Fatalf("Expected Bitmap128 to have no empty containers")
+		}
+	}
+}
+
+func TestBitmap128(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	var s, u Bitmap128
+	model, modelU := make(map[Uint128]bool), make(map[Uint128]bool)
+	for i := 0; i < 40000; i++ {
+		x := randBitmapValue(r)
+		if r.IntN(3) == 0 {
+			s.Remove(x)
+			delete(model, x)
+		} else {
+			s.Add(x)
+			model[x] = true
+		}
+		if y := randBitmapValue(r); r.IntN(2) == 0 {
+			u.Add(y)
+			modelU[y] = true
+		}
+		if s.Contains(x) != model[x] {
+			t.Fatalf("Expected Bitmap128.Contains(%s) == %t", x, model[x])
+		}
+		if i%10000 == 0 {
+			s.RunOptimize()
+			checkBitmap(t, &s, model)
+		}
+	}
+	kinds := make(map[byte]bool)
+	s.RunOptimize()
+	for _, c := range s.containers {
+		switch c.(type) {
+		case *arrayContainer:
+			kinds[kindArray] = true
+		case *bitmapContainer:
+			kinds[kindBitmap] = true
+		case *runContainer:
+			kinds[kindRun] = true
+		}
+	}
+	if len(kinds) != 3 {
+		t.Fatalf("Expected Bitmap128 to have every kind of container, got: %v", kinds)
+	}
+	checkBitmap(t, &s, model)
+	and, andNot, or, xor := make(map[Uint128]bool), make(map[Uint128]bool), make(map[Uint128]bool), make(map[Uint128]bool)
+	for x := range model {
+		or[x] = true
+		if modelU[x] {
+			and[x] = true
+		} else {
+			andNot[x], xor[x] = true, true
+		}
+	}
+	for x := range modelU {
+		or[x] = true
+		if !model[x] {
+			xor[x] = true
+		}
+	}
+	checkBitmap(t, s.And(&u), and)
+	checkBitmap(t, s.AndNot(&u), andNot)
+	checkBitmap(t, s.Or(&u), or)
+	checkBitmap(t, s.Xor(&u), xor)
+	checkBitmap(t, u.Xor(&s), xor)
+	// Set operations do not modify their operands
+	checkBitmap(t, &s, model)
+	checkBitmap(t, &u, modelU)
+
+	data, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var d Bitmap128
+	if err := d.UnmarshalBinary(data); err != nil {
+		t.Fatalf("Expected Bitmap128.UnmarshalBinary to succeed, got: %v", err)
+	}
+	checkBitmap(t, &d, model)
+	for x := range model {
+		d.Remove(x)
+	}
+	if n := d.Cardinality(); n != (Uint128{}) || len(d.keys) != 0 {
+		t.Errorf("Expected an empty Bitmap128 after removing every value, got: %s", n)
+	}
+}
+
+func TestBitmap128UnmarshalInvalid(t *testing.T) {
+	var s Bitmap128
+	s.Add(Uint128{lo: 1})
+	s.Add(Uint128{lo: 2})
+	data, _ := s.MarshalBinary()
+	tests := [][]byte{
+		nil,
+		data[:len(data)-1],
+		append(slices.Clone(data), 0),
+		// Unknown container kind
+		append(append(slices.Clone(data[:17]), 9), data[18:]...),
+		// Unsorted array container
+		append(slices.Clone(data[:len(data)-4]), 2, 0, 1, 0),
+		// Key with more than 112 bits
+		append([]byte{1, 1}, data[2:]...),
+	}
+	for _, inp := range tests {
+		var d Bitmap128
+		if err := d.UnmarshalBinary(inp); err == nil {
+			t.Errorf("Expected Bitmap128.UnmarshalBinary(%x) to fail", inp)
+		}
+	}
+}
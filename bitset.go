@@ -0,0 +1,144 @@
+package wide
+
+import (
+	"iter"
+	"math/bits"
+	"strconv"
+)
+
+// BitSet128 is a set of the integers 0 to 127, where i is in the set if bit i of the underlying Uint128 is set
+//
+// A BitSet128 converts to and from a Uint128 at no cost, e.g. BitSet128(x) and Uint128(s). Like a Uint128, it is a value,
+// so the methods which modify a set return the new set.
+type BitSet128 Uint128
+
+// bitSetWord returns the word of a BitSet128 which holds bit i, and the mask of bit i within it
+//
+// It panics if i is out of range.
+func (s *BitSet128) bitSetWord(i int) (*uint64, uint64) {
+	if uint(i) >= int128Size {
+		panic("wide: BitSet128 index out of range")
+	}
+	if i >= int64Size {
+		return &s.hi, 1 << (i - int64Size)
+	}
+	return &s.lo, 1 << i
+}
+
+// All returns an iterator over the members of a BitSet128, in ascending order
+func (s BitSet128) All() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for w, base := s.lo, 0; base < int128Size; w, base = s.hi, base+int64Size {
+			for ; w != 0; w &= w - 1 {
+				if !yield(base + bits.TrailingZeros64(w)) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Clear returns a BitSet128 without i
+//
+// Clear panics if i is not in [0, 128).
+func (s BitSet128) Clear(i int) BitSet128 {
+	w, m := s.bitSetWord(i)
+	*w &^= m
+	return s
+}
+
+// Complement returns the members of [0, 128) which are not in a BitSet128
+func (s BitSet128) Complement() BitSet128 {
+	return BitSet128{hi: ^s.hi, lo: ^s.lo}
+}
+
+// Count returns the number of members of a BitSet128
+func (s BitSet128) Count() int {
+	return bits.OnesCount64(s.hi) + bits.OnesCount64(s.lo)
+}
+
+// Difference returns the members of s which are not in t
+func (s BitSet128) Difference(t BitSet128) BitSet128 {
+	return BitSet128{hi: s.hi &^ t.hi, lo: s.lo &^ t.lo}
+}
+
+// Intersect returns the members of both BitSet128's
+func (s BitSet128) Intersect(t BitSet128) BitSet128 {
+	return BitSet128{hi: s.hi & t.hi, lo: s.lo & t.lo}
+}
+
+// IsSubset returns whether every member of s is in t
+func (s BitSet128) IsSubset(t BitSet128) bool {
+	return s.hi&^t.hi == 0 && s.lo&^t.lo == 0
+}
+
+// NextSet returns the least member of a BitSet128 which is greater than or equal to i, if any
+func (s BitSet128) NextSet(i int) (int, bool) {
+	switch {
+	case i >= int128Size:
+		return 0, false
+	case i < 0:
+		i = 0
+	}
+	// Shifting by i drops the members below i
+	x := Uint128(s).RShiftN(uint(i))
+	if x.hi == 0 && x.lo == 0 {
+		return 0, false
+	}
+	return i + int(x.TrailingZeros()), true
+}
+
+// PrevSet returns the greatest member of a BitSet128 which is less than or equal to i, if any
+func (s BitSet128) PrevSet(i int) (int, bool) {
+	switch {
+	case i < 0:
+		return 0, false
+	case i >= int128Size:
+		i = int128Size - 1
+	}
+	// Shifting by 127-i drops the members above i
+	x := Uint128(s).LShiftN(uint(int128Size - 1 - i))
+	if x.hi == 0 && x.lo == 0 {
+		return 0, false
+	}
+	return i - (int128Size - int(x.Len())), true
+}
+
+// Set returns a BitSet128 with i
+//
+// Set panics if i is not in [0, 128).
+func (s BitSet128) Set(i int) BitSet128 {
+	w, m := s.bitSetWord(i)
+	*w |= m
+	return s
+}
+
+// String returns the members of a BitSet128 in ascending order, e.g. "{0 3 127}"
+func (s BitSet128) String() string {
+	buf := []byte{'{'}
+	for i := range s.All() {
+		if len(buf) > 1 {
+			buf = append(buf, ' ')
+		}
+		buf = strconv.AppendInt(buf, int64(i), 10)
+	}
+	return string(append(buf, '}'))
+}
+
+// SymmetricDifference returns the members of exactly one of two BitSet128's
+func (s BitSet128) SymmetricDifference(t BitSet128) BitSet128 {
+	return BitSet128{hi: s.hi ^ t.hi, lo: s.lo ^ t.lo}
+}
+
+// Test returns whether i is a member of a BitSet128
+//
+// Test panics if i is not in [0, 128).
+func (s BitSet128) Test(i int) bool {
+	w, m := s.bitSetWord(i)
+	return *w&m != 0
+}
+
+// Union returns the members of either BitSet128
+func (s BitSet128) Union(t BitSet128) BitSet128 {
+	return BitSet128{hi: s.hi | t.hi, lo: s.lo | t.lo}
+}
@@ -0,0 +1,83 @@
+package wide
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestBitSet128(t *testing.T) {
+	var s BitSet128
+	for _, i := range []int{0, 3, 63, 64, 100, 127} {
+		s = s.Set(i)
+	}
+	if expected := "{0 3 63 64 100 127}"; s.String() != expected {
+		t.Errorf("Expected BitSet128.String() == %s, got: %s", expected, s.String())
+	}
+	if expected := (Uint128{hi: 1<<63 | 1<<36 | 1, lo: 1<<63 | 1<<3 | 1}); Uint128(s) != expected {
+		t.Errorf("Expected Uint128(BitSet128) == %s, got: %s", expected, Uint128(s))
+	}
+	if s.Count() != 6 || !s.Test(64) || s.Test(65) {
+		t.Errorf("Expected BitSet128 %s to have 6 members including 64 but not 65", s)
+	}
+	if s = s.Clear(64).Clear(65); s.Test(64) || s.Count() != 5 {
+		t.Errorf("Expected BitSet128.Clear(64) to remove 64, got: %s", s)
+	}
+	if (BitSet128{}).String() != "{}" {
+		t.Errorf("Expected the empty BitSet128 to be {}, got: %s", BitSet128{})
+	}
+}
+
+func TestBitSet128Random(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for i := 0; i < 1000; i++ {
+		// Sparse sets, so that NextSet and PrevSet skip over gaps
+		s, u := BitSet128{hi: r.Uint64() & r.Uint64() & r.Uint64(), lo: r.Uint64() & r.Uint64()}, BitSet128{hi: r.Uint64(), lo: r.Uint64()}
+		var members []int
+		for j := 0; j < int128Size; j++ {
+			if Uint128(s).RShiftN(uint(j)).lo&1 == 1 {
+				members = append(members, j)
+			}
+		}
+		if got := slices.Collect(s.All()); !slices.Equal(got, members) {
+			t.Fatalf("Expected BitSet128.All() == %v, got: %v", members, got)
+		}
+		for j := -1; j <= int128Size; j++ {
+			n, ok := s.NextSet(j)
+			k, found := slices.BinarySearch(members, max(j, 0))
+			if k < len(members) != ok || ok && n != members[k] {
+				t.Fatalf("Expected %s.NextSet(%d) at index %d, got: %d, %t", s, j, k, n, ok)
+			}
+			p, ok := s.PrevSet(j)
+			if found {
+				k++
+			}
+			if j >= 0 && (k > 0) != ok || ok && p != members[k-1] || j < 0 && ok {
+				t.Fatalf("Expected %s.PrevSet(%d) at index %d, got: %d, %t", s, j, k-1, p, ok)
+			}
+		}
+		inter, union := s.Intersect(u), s.Union(u)
+		if !inter.IsSubset(s) || !inter.IsSubset(u) || !s.IsSubset(union) || s.Difference(u).Intersect(u) != (BitSet128{}) {
+			t.Fatalf("Expected the set algebra of %s and %s to be consistent", s, u)
+		}
+		if s.SymmetricDifference(u) != union.Difference(inter) || s.Complement().Intersect(s) != (BitSet128{}) {
+			t.Fatalf("Expected the set algebra of %s and %s to be consistent", s, u)
+		}
+		if s.Count()+s.Complement().Count() != int128Size {
+			t.Fatalf("Expected %s and its complement to have 128 members", s)
+		}
+	}
+}
+
+func TestBitSet128Panic(t *testing.T) {
+	for _, i := range []int{-1, 128} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("Expected BitSet128.Set(%d) to panic", i)
+				}
+			}()
+			BitSet128{}.Set(i)
+		}()
+	}
+}
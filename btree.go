@@ -0,0 +1,294 @@
+package wide
+
+import (
+	"iter"
+	"slices"
+)
+
+// btreeDegree is the minimum degree of a B-tree, i.e. every node but the root has between btreeDegree-1 and 2*btreeDegree-1 keys
+const btreeDegree = 16
+
+// btreeKey is the constraint on the keys of a B-tree, which is satisfied by Uint128 and Int128
+type btreeKey[K any] interface {
+	comparable
+	Cmp(K) int
+}
+
+// btree is an in-memory B-tree, as in Introduction to Algorithms (Cormen et al.), chapter 18
+//
+// Insertion splits full nodes, and deletion refills minimal nodes, on the way down, so that neither has to walk back up the tree.
+type btree[K btreeKey[K], V any] struct {
+	root   *btreeNode[K, V]
+	length int
+}
+
+// btreeNode is a node of a B-tree, which is a leaf if it has no children
+type btreeNode[K btreeKey[K], V any] struct {
+	keys     []K
+	vals     []V
+	children []*btreeNode[K, V]
+}
+
+// ascend calls yield for the entries of a B-tree in ascending order, from lo (or the first key) to hi (or the last key)
+//
+// It returns false once yield returns false, or once a key greater than hi is reached.
+func (n *btreeNode[K, V]) ascend(lo, hi *K, yield func(K, V) bool) bool {
+	i := 0
+	if lo != nil {
+		i, _ = n.search(*lo)
+	}
+	for ; i < len(n.keys); i++ {
+		if !n.leaf() && !n.children[i].ascend(lo, hi, yield) {
+			return false
+		}
+		if hi != nil && n.keys[i].Cmp(*hi) > 0 || !yield(n.keys[i], n.vals[i]) {
+			return false
+		}
+	}
+	return n.leaf() || n.children[i].ascend(lo, hi, yield)
+}
+
+// leaf returns whether a node is a leaf
+func (n *btreeNode[K, V]) leaf() bool {
+	return len(n.children) == 0
+}
+
+// merge merges child i+1 and key i into child i
+func (n *btreeNode[K, V]) merge(i int) {
+	left, right := n.children[i], n.children[i+1]
+	left.keys = append(append(left.keys, n.keys[i]), right.keys...)
+	left.vals = append(append(left.vals, n.vals[i]), right.vals...)
+	left.children = append(left.children, right.children...)
+	n.keys = slices.Delete(n.keys, i, i+1)
+	n.vals = slices.Delete(n.vals, i, i+1)
+	n.children = slices.Delete(n.children, i+1, i+2)
+}
+
+// refill ensures that child i has at least btreeDegree keys before descending into it, and returns the index of the child
+// which then holds its keys
+func (n *btreeNode[K, V]) refill(i int) int {
+	c := n.children[i]
+	if len(c.keys) >= btreeDegree {
+		return i
+	}
+	switch {
+	case i > 0 && len(n.children[i-1].keys) >= btreeDegree:
+		// Rotate the last key of the left sibling through the parent
+		l := n.children[i-1]
+		last := len(l.keys) - 1
+		c.keys = slices.Insert(c.keys, 0, n.keys[i-1])
+		c.vals = slices.Insert(c.vals, 0, n.vals[i-1])
+		n.keys[i-1], n.vals[i-1] = l.keys[last], l.vals[last]
+		l.keys, l.vals = slices.Delete(l.keys, last, last+1), slices.Delete(l.vals, last, last+1)
+		if !l.leaf() {
+			c.children = slices.Insert(c.children, 0, l.children[last+1])
+			l.children = slices.Delete(l.children, last+1, last+2)
+		}
+		return i
+	case i < len(n.keys) && len(n.children[i+1].keys) >= btreeDegree:
+		// Rotate the first key of the right sibling through the parent
+		r := n.children[i+1]
+		c.keys = append(c.keys, n.keys[i])
+		c.vals = append(c.vals, n.vals[i])
+		n.keys[i], n.vals[i] = r.keys[0], r.vals[0]
+		r.keys, r.vals = slices.Delete(r.keys, 0, 1), slices.Delete(r.vals, 0, 1)
+		if !r.leaf() {
+			c.children = append(c.children, r.children[0])
+			r.children = slices.Delete(r.children, 0, 1)
+		}
+		return i
+	case i < len(n.keys):
+		n.merge(i)
+		return i
+	default:
+		n.merge(i - 1)
+		return i - 1
+	}
+}
+
+// remove removes a key from the subtree of a node, which has at least btreeDegree keys unless it is the root
+func (n *btreeNode[K, V]) remove(k K) (V, bool) {
+	i, found := n.search(k)
+	switch {
+	case n.leaf() && !found:
+		var zero V
+		return zero, false
+	case n.leaf():
+		v := n.vals[i]
+		n.keys, n.vals = slices.Delete(n.keys, i, i+1), slices.Delete(n.vals, i, i+1)
+		return v, true
+	case found && len(n.children[i].keys) >= btreeDegree:
+		// Replace the key with its predecessor, which is then removed from the left child
+		v := n.vals[i]
+		p := n.children[i]
+		for !p.leaf() {
+			p = p.children[len(p.children)-1]
+		}
+		n.keys[i], n.vals[i] = p.keys[len(p.keys)-1], p.vals[len(p.vals)-1]
+		n.children[i].remove(n.keys[i])
+		return v, true
+	case found && len(n.children[i+1].keys) >= btreeDegree:
+		// Replace the key with its successor, which is then removed from the right child
+		v := n.vals[i]
+		s := n.children[i+1]
+		for !s.leaf() {
+			s = s.children[0]
+		}
+		n.keys[i], n.vals[i] = s.keys[0], s.vals[0]
+		n.children[i+1].remove(n.keys[i])
+		return v, true
+	case found:
+		n.merge(i)
+		return n.children[i].remove(k)
+	default:
+		return n.children[n.refill(i)].remove(k)
+	}
+}
+
+// search returns the index of the first key of a node which is not less than k, and whether it is equal to k
+func (n *btreeNode[K, V]) search(k K) (int, bool) {
+	lo, hi := 0, len(n.keys)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if n.keys[mid].Cmp(k) < 0 {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo, lo < len(n.keys) && n.keys[lo] == k
+}
+
+// split splits the full child i into two nodes, and moves its median key up into the node
+func (n *btreeNode[K, V]) split(i int) {
+	c := n.children[i]
+	right := &btreeNode[K, V]{
+		keys: slices.Clone(c.keys[btreeDegree:]),
+		vals: slices.Clone(c.vals[btreeDegree:]),
+	}
+	if !c.leaf() {
+		right.children = slices.Clone(c.children[btreeDegree:])
+		clear(c.children[btreeDegree:])
+		c.children = c.children[:btreeDegree]
+	}
+	n.keys = slices.Insert(n.keys, i, c.keys[btreeDegree-1])
+	n.vals = slices.Insert(n.vals, i, c.vals[btreeDegree-1])
+	n.children = slices.Insert(n.children, i+1, right)
+	clear(c.vals[btreeDegree-1:])
+	c.keys, c.vals = c.keys[:btreeDegree-1], c.vals[:btreeDegree-1]
+}
+
+// all returns an iterator over the entries of a B-tree between lo and hi, where nil bounds are unbounded
+func (t *btree[K, V]) all(lo, hi *K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if t.root != nil {
+			t.root.ascend(lo, hi, yield)
+		}
+	}
+}
+
+// ceiling returns the entry with the least key which is not less than k
+func (t *btree[K, V]) ceiling(k K) (key K, v V, ok bool) {
+	for n := t.root; n != nil; {
+		i, found := n.search(k)
+		if i < len(n.keys) {
+			key, v, ok = n.keys[i], n.vals[i], true
+		}
+		if found || n.leaf() {
+			break
+		}
+		n = n.children[i]
+	}
+	return key, v, ok
+}
+
+// delete removes the entry with key k, and returns its value
+func (t *btree[K, V]) delete(k K) (V, bool) {
+	if t.root == nil {
+		var zero V
+		return zero, false
+	}
+	v, ok := t.root.remove(k)
+	if ok {
+		t.length--
+	}
+	if len(t.root.keys) == 0 {
+		if t.root.leaf() {
+			t.root = nil
+		} else {
+			t.root = t.root.children[0]
+		}
+	}
+	return v, ok
+}
+
+// floor returns the entry with the greatest key which is not greater than k
+func (t *btree[K, V]) floor(k K) (key K, v V, ok bool) {
+	for n := t.root; n != nil; {
+		i, found := n.search(k)
+		if found {
+			return n.keys[i], n.vals[i], true
+		}
+		if i > 0 {
+			key, v, ok = n.keys[i-1], n.vals[i-1], true
+		}
+		if n.leaf() {
+			break
+		}
+		n = n.children[i]
+	}
+	return key, v, ok
+}
+
+// get returns the value of the entry with key k
+func (t *btree[K, V]) get(k K) (V, bool) {
+	for n := t.root; n != nil; {
+		i, found := n.search(k)
+		if found {
+			return n.vals[i], true
+		}
+		if n.leaf() {
+			break
+		}
+		n = n.children[i]
+	}
+	var zero V
+	return zero, false
+}
+
+// put sets the value of the entry with key k, inserting it if it does not exist
+func (t *btree[K, V]) put(k K, v V) {
+	if t.root == nil {
+		t.root = &btreeNode[K, V]{keys: []K{k}, vals: []V{v}}
+		t.length++
+		return
+	}
+	if len(t.root.keys) == 2*btreeDegree-1 {
+		t.root = &btreeNode[K, V]{children: []*btreeNode[K, V]{t.root}}
+		t.root.split(0)
+	}
+	n := t.root
+	for {
+		i, found := n.search(k)
+		if found {
+			n.vals[i] = v
+			return
+		}
+		if n.leaf() {
+			n.keys, n.vals = slices.Insert(n.keys, i, k), slices.Insert(n.vals, i, v)
+			t.length++
+			return
+		}
+		if len(n.children[i].keys) == 2*btreeDegree-1 {
+			n.split(i)
+			switch c := k.Cmp(n.keys[i]); {
+			case c == 0:
+				n.vals[i] = v
+				return
+			case c > 0:
+				i++
+			}
+		}
+		n = n.children[i]
+	}
+}
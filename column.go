@@ -0,0 +1,169 @@
+package wide
+
+// Int128Column is a column of Int128's, stored as separate slices of high and low words
+//
+// Keeping the words apart halves the memory touched by filters which can be decided by the high words alone, such as Between.
+// The zero value is an empty column. Int128Column implements sort.Interface.
+type Int128Column struct {
+	hi []int64
+	lo []uint64
+}
+
+// Uint128Column is a column of Uint128's, stored as separate slices of high and low words
+//
+// Keeping the words apart halves the memory touched by filters which can be decided by the high words alone, such as Between.
+// The zero value is an empty column. Uint128Column implements sort.Interface.
+type Uint128Column struct {
+	hi, lo []uint64
+}
+
+// NewInt128Column returns a column holding a copy of xs
+func NewInt128Column(xs []Int128) *Int128Column {
+	c := &Int128Column{hi: make([]int64, 0, len(xs)), lo: make([]uint64, 0, len(xs))}
+	c.Append(xs...)
+	return c
+}
+
+// NewUint128Column returns a column holding a copy of xs
+func NewUint128Column(xs []Uint128) *Uint128Column {
+	c := &Uint128Column{hi: make([]uint64, 0, len(xs)), lo: make([]uint64, 0, len(xs))}
+	c.Append(xs...)
+	return c
+}
+
+// Append appends values to an Int128Column
+func (c *Int128Column) Append(xs ...Int128) {
+	for _, x := range xs {
+		c.hi = append(c.hi, x.hi)
+		c.lo = append(c.lo, x.lo)
+	}
+}
+
+// Between returns the ascending indices of the values x in an Int128Column for which lo <= x <= hi
+func (c *Int128Column) Between(lo, hi Int128) (idx []int) {
+	los := c.lo[:len(c.hi)]
+	for i, h := range c.hi {
+		// The low word only needs to be read when the high word is equal to one of the bounds
+		if h < lo.hi || h > hi.hi || h == lo.hi && los[i] < lo.lo || h == hi.hi && los[i] > hi.lo {
+			continue
+		}
+		idx = append(idx, i)
+	}
+	return idx
+}
+
+// Get returns the value at index i of an Int128Column
+func (c *Int128Column) Get(i int) Int128 {
+	return Int128{hi: c.hi[i], lo: c.lo[i]}
+}
+
+// Int128s returns the values of an Int128Column as a new slice of Int128's
+func (c *Int128Column) Int128s() []Int128 {
+	xs := make([]Int128, len(c.hi))
+	los := c.lo[:len(xs)]
+	for i := range xs {
+		xs[i] = Int128{hi: c.hi[i], lo: los[i]}
+	}
+	return xs
+}
+
+// Len returns the number of values in an Int128Column
+func (c *Int128Column) Len() int {
+	return len(c.hi)
+}
+
+// Less returns whether the value at index i of an Int128Column is less than the value at index j
+func (c *Int128Column) Less(i, j int) bool {
+	return c.hi[i] < c.hi[j] || c.hi[i] == c.hi[j] && c.lo[i] < c.lo[j]
+}
+
+// Set sets the value at index i of an Int128Column
+func (c *Int128Column) Set(i int, x Int128) {
+	c.hi[i], c.lo[i] = x.hi, x.lo
+}
+
+// Sort sorts an Int128Column in ascending order
+//
+// The values are gathered into a contiguous slice, sorted with SortInt128s and scattered back, which is about twice as fast
+// as sorting through sort.Interface (see BenchmarkUint128ColumnSort), since every Swap would touch both slices.
+func (c *Int128Column) Sort() {
+	xs := c.Int128s()
+	SortInt128s(xs)
+	for i, x := range xs {
+		c.hi[i], c.lo[i] = x.hi, x.lo
+	}
+}
+
+// Swap swaps the values at indices i and j of an Int128Column
+func (c *Int128Column) Swap(i, j int) {
+	c.hi[i], c.hi[j] = c.hi[j], c.hi[i]
+	c.lo[i], c.lo[j] = c.lo[j], c.lo[i]
+}
+
+// Append appends values to a Uint128Column
+func (c *Uint128Column) Append(xs ...Uint128) {
+	for _, x := range xs {
+		c.hi = append(c.hi, x.hi)
+		c.lo = append(c.lo, x.lo)
+	}
+}
+
+// Between returns the ascending indices of the values x in a Uint128Column for which lo <= x <= hi
+func (c *Uint128Column) Between(lo, hi Uint128) (idx []int) {
+	los := c.lo[:len(c.hi)]
+	for i, h := range c.hi {
+		// The low word only needs to be read when the high word is equal to one of the bounds
+		if h < lo.hi || h > hi.hi || h == lo.hi && los[i] < lo.lo || h == hi.hi && los[i] > hi.lo {
+			continue
+		}
+		idx = append(idx, i)
+	}
+	return idx
+}
+
+// Get returns the value at index i of a Uint128Column
+func (c *Uint128Column) Get(i int) Uint128 {
+	return Uint128{hi: c.hi[i], lo: c.lo[i]}
+}
+
+// Len returns the number of values in a Uint128Column
+func (c *Uint128Column) Len() int {
+	return len(c.hi)
+}
+
+// Less returns whether the value at index i of a Uint128Column is less than the value at index j
+func (c *Uint128Column) Less(i, j int) bool {
+	return c.hi[i] < c.hi[j] || c.hi[i] == c.hi[j] && c.lo[i] < c.lo[j]
+}
+
+// Set sets the value at index i of a Uint128Column
+func (c *Uint128Column) Set(i int, x Uint128) {
+	c.hi[i], c.lo[i] = x.hi, x.lo
+}
+
+// Sort sorts a Uint128Column in ascending order
+//
+// The values are gathered into a contiguous slice, sorted with SortUint128s and scattered back, as in Int128Column.Sort.
+func (c *Uint128Column) Sort() {
+	xs := c.Uint128s()
+	SortUint128s(xs)
+	for i, x := range xs {
+		c.hi[i], c.lo[i] = x.hi, x.lo
+	}
+}
+
+// Swap swaps the values at indices i and j of a Uint128Column
+func (c *Uint128Column) Swap(i, j int) {
+	c.hi[i], c.hi[j] = c.hi[j], c.hi[i]
+	c.lo[i], c.lo[j] = c.lo[j], c.lo[i]
+}
+
+// Uint128s returns the values of a Uint128Column as a new slice of Uint128's
+func (c *Uint128Column) Uint128s() []Uint128 {
+	xs := make([]Uint128, len(c.hi))
+	los := c.lo[:len(xs)]
+	for i := range xs {
+		xs[i] = Uint128{hi: c.hi[i], lo: los[i]}
+	}
+	return xs
+}
@@ -0,0 +1,123 @@
+package wide
+
+import (
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"testing"
+)
+
+var (
+	_ sort.Interface = (*Int128Column)(nil)
+	_ sort.Interface = (*Uint128Column)(nil)
+)
+
+func TestUint128Column(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	// Few distinct high words, so that Between has to compare the low words too
+	xs := make([]Uint128, 500)
+	for i := range xs {
+		xs[i] = Uint128{hi: r.Uint64N(4), lo: r.Uint64()}
+	}
+	c := NewUint128Column(xs[:100])
+	c.Append(xs[100:]...)
+	if c.Len() != len(xs) || !slices.Equal(c.Uint128s(), xs) {
+		t.Fatalf("Expected Uint128Column to hold the appended values")
+	}
+	c.Set(7, Uint128{hi: 9})
+	if result := c.Get(7); result != (Uint128{hi: 9}) {
+		t.Errorf("Expected Uint128Column.Get(7) == 0x90000000000000000, got: %s", result)
+	}
+	xs[7] = Uint128{hi: 9}
+	for i := 0; i < 50; i++ {
+		lo, hi := xs[r.IntN(len(xs))], xs[r.IntN(len(xs))]
+		var expected []int
+		for j, x := range xs {
+			if lo.Lte(x) && x.Lte(hi) {
+				expected = append(expected, j)
+			}
+		}
+		if result := c.Between(lo, hi); !slices.Equal(result, expected) {
+			t.Errorf("Expected Uint128Column.Between(%s, %s) == %v, got: %v", lo, hi, expected, result)
+		}
+	}
+	c.Sort()
+	slices.SortFunc(xs, Uint128.Cmp)
+	if !slices.Equal(c.Uint128s(), xs) {
+		t.Errorf("Expected Uint128Column.Sort() to sort the column")
+	}
+}
+
+func TestSortColumns(t *testing.T) {
+	// Large enough to take the radix sort path
+	r := rand.New(rand.NewPCG(1, 2))
+	xs, ys := make([]Uint128, 2*radixSortThreshold), make([]Int128, 2*radixSortThreshold)
+	for i := range xs {
+		xs[i] = Uint128{hi: r.Uint64(), lo: r.Uint64()}
+		ys[i] = Int128{hi: r.Int64N(8) - 4, lo: r.Uint64()}
+	}
+	c, d := NewUint128Column(xs), NewInt128Column(ys)
+	c.Sort()
+	d.Sort()
+	slices.SortFunc(xs, Uint128.Cmp)
+	slices.SortFunc(ys, Int128.Cmp)
+	if !slices.Equal(c.Uint128s(), xs) {
+		t.Errorf("Expected Uint128Column.Sort() to sort the column")
+	}
+	if !slices.Equal(d.Int128s(), ys) {
+		t.Errorf("Expected Int128Column.Sort() to sort the column")
+	}
+}
+
+func TestInt128Column(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	xs := make([]Int128, 500)
+	for i := range xs {
+		xs[i] = Int128{hi: r.Int64N(4) - 2, lo: r.Uint64()}
+	}
+	var c Int128Column
+	c.Append(xs...)
+	if c.Len() != len(xs) || !slices.Equal(c.Int128s(), xs) {
+		t.Fatalf("Expected Int128Column to hold the appended values")
+	}
+	c.Set(3, Int128{hi: -9})
+	if result := c.Get(3); result != (Int128{hi: -9}) {
+		t.Errorf("Expected Int128Column.Get(3) == %s, got: %s", Int128{hi: -9}, result)
+	}
+	xs[3] = Int128{hi: -9}
+	for i := 0; i < 50; i++ {
+		lo, hi := xs[r.IntN(len(xs))], xs[r.IntN(len(xs))]
+		var expected []int
+		for j, x := range xs {
+			if lo.Lte(x) && x.Lte(hi) {
+				expected = append(expected, j)
+			}
+		}
+		if result := c.Between(lo, hi); !slices.Equal(result, expected) {
+			t.Errorf("Expected Int128Column.Between(%s, %s) == %v, got: %v", lo, hi, expected, result)
+		}
+	}
+	c.Sort()
+	slices.SortFunc(xs, Int128.Cmp)
+	if !slices.Equal(c.Int128s(), xs) {
+		t.Errorf("Expected Int128Column.Sort() to sort the column")
+	}
+}
+
+func BenchmarkUint128ColumnSort(b *testing.B) {
+	r := rand.New(rand.NewPCG(1, 2))
+	xs := make([]Uint128, 1<<16)
+	for i := range xs {
+		xs[i] = Uint128{hi: r.Uint64(), lo: r.Uint64()}
+	}
+	b.Run("Sort", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			NewUint128Column(xs).Sort()
+		}
+	})
+	b.Run("sort.Interface", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			sort.Sort(NewUint128Column(xs))
+		}
+	})
+}
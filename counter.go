@@ -0,0 +1,99 @@
+package wide
+
+import (
+	"math/rand/v2"
+	"runtime"
+	"sync"
+	"unsafe"
+)
+
+// cacheLine is the assumed size of a CPU cache line, which is 64 bytes on most CPUs
+const cacheLine = 64
+
+// Counter128 is a 128-bit counter for many concurrent writers, sharded to avoid contention on a single AtomicUint128
+//
+// Each Add goes to a pseudo-randomly chosen shard, and each shard is padded to its own cache line to avoid false sharing. Load
+// sums the shards, so it is slower than AtomicUint128.Load, and it is not a linearizable snapshot when it races with Add. The
+// zero value is 0, with one shard for each P. A Counter128 must not be copied after first use.
+type Counter128 struct {
+	once   sync.Once
+	shards []counterShard
+}
+
+// counterShard is a shard of a Counter128, padded to a cache line
+type counterShard struct {
+	v AtomicUint128
+	_ [(cacheLine - unsafe.Sizeof(AtomicUint128{})%cacheLine) % cacheLine]byte
+}
+
+// NewCounter128 returns a Counter128 with at least the given number of shards, rounded up to a power of two
+//
+// A non-positive number of shards defaults to GOMAXPROCS.
+func NewCounter128(shards int) *Counter128 {
+	c := new(Counter128)
+	c.once.Do(func() { c.init(shards) })
+	return c
+}
+
+// init allocates the shards of a Counter128
+func (c *Counter128) init(shards int) {
+	if shards <= 0 {
+		shards = runtime.GOMAXPROCS(0)
+	}
+	n := 1
+	for n < shards {
+		n <<= 1
+	}
+	c.shards = make([]counterShard, n)
+}
+
+// shard returns a pseudo-randomly chosen shard of a Counter128
+func (c *Counter128) shard() *AtomicUint128 {
+	c.once.Do(func() { c.init(0) })
+	return &c.shards[rand.Uint64()&uint64(len(c.shards)-1)].v
+}
+
+// Add adds delta to a Counter128
+//
+// This function overflows silently. Use delta.Neg() to subtract.
+func (c *Counter128) Add(delta Uint128) {
+	c.shard().Add(delta)
+}
+
+// Inc adds 1 to a Counter128
+func (c *Counter128) Inc() {
+	c.shard().Add(Uint128{lo: 1})
+}
+
+// Load returns the sum of the shards of a Counter128
+func (c *Counter128) Load() (z Uint128) {
+	c.once.Do(func() { c.init(0) })
+	for i := range c.shards {
+		z = z.Add(c.shards[i].v.Load())
+	}
+	return z
+}
+
+// LoadAndReset returns the value of a Counter128, and resets it to 0
+//
+// Each shard is swapped with 0 atomically, so an increment which races with LoadAndReset is counted either by this call or by
+// the next, but never lost.
+func (c *Counter128) LoadAndReset() (z Uint128) {
+	c.once.Do(func() { c.init(0) })
+	for i := range c.shards {
+		z = z.Add(c.shards[i].v.Swap(Uint128{}))
+	}
+	return z
+}
+
+// Reset sets a Counter128 to 0
+func (c *Counter128) Reset() {
+	c.LoadAndReset()
+}
+
+// Snapshot returns the value of a Counter128 without resetting it, and is equivalent to Load
+//
+// Use LoadAndReset to read and reset a Counter128 without losing increments which race with it.
+func (c *Counter128) Snapshot() Uint128 {
+	return c.Load()
+}
@@ -0,0 +1,86 @@
+package wide
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func TestCounter128(t *testing.T) {
+	var c Counter128
+	c.Add(Uint128{lo: 1<<64 - 1})
+	c.Inc()
+	if result := c.Load(); result != (Uint128{hi: 1}) {
+		t.Errorf("Expected Counter128.Load() == 0x10000000000000000, got: %s", result)
+	}
+	c.Add(Uint128{lo: 1}.Neg())
+	if result := c.Snapshot(); result != (Uint128{lo: 1<<64 - 1}) {
+		t.Errorf("Expected Counter128.Snapshot() == 0xffffffffffffffff, got: %s", result)
+	}
+	if result := c.LoadAndReset(); result != (Uint128{lo: 1<<64 - 1}) {
+		t.Errorf("Expected Counter128.LoadAndReset() == 0xffffffffffffffff, got: %s", result)
+	}
+	if result := c.Load(); result != (Uint128{}) {
+		t.Errorf("Expected Counter128.Load() == 0x0 after LoadAndReset, got: %s", result)
+	}
+	c.Inc()
+	c.Reset()
+	if result := c.Load(); result != (Uint128{}) {
+		t.Errorf("Expected Counter128.Load() == 0x0 after Reset, got: %s", result)
+	}
+	if n := len(NewCounter128(5).shards); n != 8 {
+		t.Errorf("Expected NewCounter128(5) to have 8 shards, got: %d", n)
+	}
+}
+
+func TestCounter128Concurrent(t *testing.T) {
+	const n, m = 16, 2000
+	c := NewCounter128(4)
+	var wg sync.WaitGroup
+	var resets AtomicUint128
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < m; j++ {
+				c.Add(Uint128{lo: 1 << 63})
+				if i == 0 && j%100 == 0 {
+					resets.Add(c.LoadAndReset())
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	if result, expected := resets.Load().Add(c.Load()), (Uint128{hi: n * m / 2}); result != expected {
+		t.Errorf("Expected Counter128 == %s after concurrent updates, got: %s", expected, result)
+	}
+}
+
+func BenchmarkCounter128(b *testing.B) {
+	for _, procs := range []int{1, 4, 16, 64} {
+		b.Run(fmt.Sprintf("GOMAXPROCS=%d", procs), func(b *testing.B) {
+			defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(procs))
+			c := NewCounter128(0)
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					c.Inc()
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkAtomicUint128Add(b *testing.B) {
+	for _, procs := range []int{1, 4, 16, 64} {
+		b.Run(fmt.Sprintf("GOMAXPROCS=%d", procs), func(b *testing.B) {
+			defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(procs))
+			var a AtomicUint128
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					a.Add(Uint128{lo: 1})
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,443 @@
+package wide
+
+import (
+	"errors"
+	"strconv"
+)
+
+// MaxDecimalScale is the largest scale supported by Decimal, which is the number of decimal digits that always fit in an Int128
+const MaxDecimalScale = 38
+
+// RoundingMode determines how a result is rounded when it cannot be represented exactly
+type RoundingMode byte
+
+// Rounding modes
+const (
+	RoundHalfEven RoundingMode = iota // to nearest, ties to even
+	RoundHalfUp                       // to nearest, ties away from zero
+	RoundDown                         // toward zero
+	RoundUp                           // away from zero
+	RoundCeiling                      // toward positive infinity
+	RoundFloor                        // toward negative infinity
+)
+
+// roundUp returns whether a truncated magnitude should be incremented
+//
+// half compares the discarded fraction with one half (-1, 0, or +1), inexact reports whether the discarded fraction is non-zero,
+// odd reports whether the truncated magnitude is odd, and neg reports whether the result is negative.
+func (mode RoundingMode) roundUp(half int, inexact, odd, neg bool) bool {
+	switch mode {
+	case RoundHalfEven:
+		return half > 0 || half == 0 && odd
+	case RoundHalfUp:
+		return half >= 0
+	case RoundUp:
+		return inexact
+	case RoundCeiling:
+		return inexact && !neg
+	case RoundFloor:
+		return inexact && neg
+	default: // RoundDown
+		return false
+	}
+}
+
+// Decimal is a fixed-point decimal number, represented by an Int128 coefficient and a scale (i.e. coefficient * 10^-scale)
+//
+// The scale is a property of each value, so that amounts keep the precision they were created with (e.g. "1.50" has a scale
+// of 2). The zero value is 0 with a scale of 0.
+type Decimal struct {
+	coef  Int128
+	scale uint
+}
+
+// NewDecimal returns the Decimal coef * 10^-scale
+//
+// NewDecimal panics if the scale exceeds MaxDecimalScale.
+func NewDecimal(coef Int128, scale uint) Decimal {
+	if scale > MaxDecimalScale {
+		panic("decimal scale out of range")
+	}
+	return Decimal{coef: coef, scale: scale}
+}
+
+// ParseDecimal parses a string of the form "[+-]digits[.digits]" as a Decimal, with a scale equal to the number of fractional digits
+func ParseDecimal(s string) (Decimal, error) {
+	var d Decimal
+	if err := d.UnmarshalText([]byte(s)); err != nil {
+		return Decimal{}, err
+	}
+	return d, nil
+}
+
+// Add returns the sum of two Decimal's, with the larger of their scales
+//
+// This function overflows silently, see AddChecked
+func (x Decimal) Add(y Decimal) Decimal {
+	x, y = x.align(y)
+	x.coef = x.coef.Add(y.coef)
+	return x
+}
+
+// AddChecked returns the sum of two Decimal's, with the larger of their scales, and whether the sum was computed without overflowing
+func (x Decimal) AddChecked(y Decimal) (z Decimal, ok bool) {
+	x, y, ok = x.alignChecked(y)
+	z = x
+	z.coef = x.coef.Add(y.coef)
+	// The sum overflows if and only if the operands have the same sign, and the result has the opposite sign
+	return z, ok && (x.coef.IsNeg() != y.coef.IsNeg() || z.coef.IsNeg() == x.coef.IsNeg())
+}
+
+// align returns x and y rescaled (exactly) to the larger of their scales
+func (x Decimal) align(y Decimal) (Decimal, Decimal) {
+	switch {
+	case x.scale < y.scale:
+		x.coef = x.coef.Mul(pow10tab[y.scale-x.scale].Int128())
+		x.scale = y.scale
+	case x.scale > y.scale:
+		y.coef = y.coef.Mul(pow10tab[x.scale-y.scale].Int128())
+		y.scale = x.scale
+	}
+	return x, y
+}
+
+// alignChecked returns x and y rescaled (exactly) to the larger of their scales, and whether neither coefficient overflowed
+func (x Decimal) alignChecked(y Decimal) (Decimal, Decimal, bool) {
+	scale := max(x.scale, y.scale)
+	x, xok := x.scaleUpChecked(scale)
+	y, yok := y.scaleUpChecked(scale)
+	return x, y, xok && yok
+}
+
+// Cmp compares x and y numerically (regardless of scale) and returns:
+//
+//   -1 if x <  y
+//    0 if x == y
+//   +1 if x >  y
+func (x Decimal) Cmp(y Decimal) int {
+	if xs, ys := x.coef.Sign(), y.coef.Sign(); xs != ys || xs == 0 {
+		switch {
+		case xs < ys:
+			return -1
+		case xs > ys:
+			return 1
+		default:
+			return 0
+		}
+	}
+	// Compare the exact magnitudes at a common scale, which may need 256 bits
+	xhi, xlo := scaleUp256(Uint128{}, x.coef.Abs().Uint128(), max(x.scale, y.scale)-x.scale)
+	yhi, ylo := scaleUp256(Uint128{}, y.coef.Abs().Uint128(), max(x.scale, y.scale)-y.scale)
+	c := xhi.Cmp(yhi)
+	if c == 0 {
+		c = xlo.Cmp(ylo)
+	}
+	return c * x.coef.Sign()
+}
+
+// Coefficient returns the coefficient of a Decimal
+func (x Decimal) Coefficient() Int128 {
+	return x.coef
+}
+
+// MarshalJSON implements the json.Marshaler interface, encoding the Decimal as a JSON string
+func (x Decimal) MarshalJSON() ([]byte, error) {
+	return strconv.AppendQuote(nil, x.String()), nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface
+func (x Decimal) MarshalText() ([]byte, error) {
+	return []byte(x.String()), nil
+}
+
+// Mul returns the product of two Decimal's, rounded to the given scale
+//
+// Mul panics if the scale exceeds MaxDecimalScale. The product is computed exactly before rounding. This function overflows
+// silently, see MulChecked
+func (x Decimal) Mul(y Decimal, scale uint, mode RoundingMode) Decimal {
+	z, _ := x.MulChecked(y, scale, mode)
+	return z
+}
+
+// MulChecked returns the product of two Decimal's, rounded to the given scale, and whether it was computed without overflowing
+//
+// MulChecked panics if the scale exceeds MaxDecimalScale.
+func (x Decimal) MulChecked(y Decimal, scale uint, mode RoundingMode) (z Decimal, ok bool) {
+	if scale > MaxDecimalScale {
+		panic("decimal scale out of range")
+	}
+	neg := x.coef.IsNeg() != y.coef.IsNeg()
+	hi, lo := x.coef.Abs().Uint128().mulFull(y.coef.Abs().Uint128())
+	if s := x.scale + y.scale; s > scale {
+		hi, lo = divRound256(hi, lo, Uint128{lo: 1}, s-scale, neg, mode)
+	} else {
+		hi, lo = scaleUp256(hi, lo, scale-s)
+	}
+	return Decimal{coef: signed128(lo, neg), scale: scale}, fitsInt128(hi, lo, neg)
+}
+
+// Neg returns the additive inverse of a Decimal
+func (x Decimal) Neg() Decimal {
+	x.coef = x.coef.Neg()
+	return x
+}
+
+// Quo returns the quotient of two Decimal's, rounded to the given scale
+//
+// Quo panics on division by 0, or if the scale exceeds MaxDecimalScale. This function overflows silently, see QuoChecked
+func (x Decimal) Quo(y Decimal, scale uint, mode RoundingMode) Decimal {
+	z, _ := x.QuoChecked(y, scale, mode)
+	return z
+}
+
+// QuoChecked returns the quotient of two Decimal's, rounded to the given scale, and whether it was computed without
+// overflowing
+//
+// QuoChecked panics on division by 0, or if the scale exceeds MaxDecimalScale.
+func (x Decimal) QuoChecked(y Decimal, scale uint, mode RoundingMode) (z Decimal, ok bool) {
+	if scale > MaxDecimalScale {
+		panic("decimal scale out of range")
+	}
+	if y.coef.Sign() == 0 {
+		panic("runtime error: integer divide by zero")
+	}
+	neg := x.coef.IsNeg() != y.coef.IsNeg()
+	// x/y = (cx * 10^(sy + scale - sx) / cy) * 10^-scale
+	var hi, lo Uint128
+	if e := int(y.scale+scale) - int(x.scale); e >= 0 {
+		hi, lo = scaleUp256(Uint128{}, x.coef.Abs().Uint128(), uint(e))
+		hi, lo = divRound256(hi, lo, y.coef.Abs().Uint128(), 0, neg, mode)
+	} else {
+		hi, lo = divRound256(Uint128{}, x.coef.Abs().Uint128(), y.coef.Abs().Uint128(), uint(-e), neg, mode)
+	}
+	return Decimal{coef: signed128(lo, neg), scale: scale}, fitsInt128(hi, lo, neg)
+}
+
+// Rescale returns x with the given scale, rounding if the scale is decreased
+//
+// Rescale panics if the scale exceeds MaxDecimalScale. This function overflows silently, see RescaleChecked
+func (x Decimal) Rescale(scale uint, mode RoundingMode) Decimal {
+	z, _ := x.RescaleChecked(scale, mode)
+	return z
+}
+
+// RescaleChecked returns x with the given scale, rounding if the scale is decreased, and whether it was computed without
+// overflowing
+//
+// RescaleChecked panics if the scale exceeds MaxDecimalScale.
+func (x Decimal) RescaleChecked(scale uint, mode RoundingMode) (z Decimal, ok bool) {
+	if scale > MaxDecimalScale {
+		panic("decimal scale out of range")
+	}
+	if x.scale <= scale {
+		return x.scaleUpChecked(scale)
+	}
+	neg := x.coef.IsNeg()
+	hi, lo := divRound256(Uint128{}, x.coef.Abs().Uint128(), Uint128{lo: 1}, x.scale-scale, neg, mode)
+	return Decimal{coef: signed128(lo, neg), scale: scale}, fitsInt128(hi, lo, neg)
+}
+
+// Scale returns the scale of a Decimal
+func (x Decimal) Scale() uint {
+	return x.scale
+}
+
+// scaleUpChecked returns x rescaled (exactly) to a scale no smaller than its own, and whether the coefficient did not overflow
+func (x Decimal) scaleUpChecked(scale uint) (Decimal, bool) {
+	neg := x.coef.IsNeg()
+	hi, lo := scaleUp256(Uint128{}, x.coef.Abs().Uint128(), scale-x.scale)
+	x.coef, x.scale = signed128(lo, neg), scale
+	return x, fitsInt128(hi, lo, neg)
+}
+
+// Sign returns the sign of a Decimal
+func (x Decimal) Sign() int {
+	return x.coef.Sign()
+}
+
+// String returns a decimal representation of a Decimal, with exactly as many fractional digits as its scale
+func (x Decimal) String() string {
+	var buf []byte
+	if x.coef.IsNeg() {
+		buf = append(buf, '-')
+	}
+	digits := appendDecimal(nil, x.coef.Abs().Uint128())
+	if uint(len(digits)) <= x.scale {
+		// Pad with leading zeros so that there is a single zero before the decimal point
+		buf = append(buf, '0')
+		for i := uint(len(digits)); i < x.scale; i++ {
+			digits = append([]byte{'0'}, digits...)
+		}
+	}
+	point := uint(len(digits)) - x.scale
+	buf = append(buf, digits[:point]...)
+	if x.scale > 0 {
+		buf = append(buf, '.')
+		buf = append(buf, digits[point:]...)
+	}
+	return string(buf)
+}
+
+// Sub returns the difference of two Decimal's, with the larger of their scales
+//
+// This function overflows silently, see SubChecked
+func (x Decimal) Sub(y Decimal) Decimal {
+	x, y = x.align(y)
+	x.coef = x.coef.Sub(y.coef)
+	return x
+}
+
+// SubChecked returns the difference of two Decimal's, with the larger of their scales, and whether the difference was computed
+// without overflowing
+func (x Decimal) SubChecked(y Decimal) (z Decimal, ok bool) {
+	x, y, ok = x.alignChecked(y)
+	z = x
+	z.coef = x.coef.Sub(y.coef)
+	// The difference overflows if and only if the operands have opposite signs, and the result has the sign of y
+	return z, ok && (x.coef.IsNeg() == y.coef.IsNeg() || z.coef.IsNeg() == x.coef.IsNeg())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, accepting either a JSON string or a JSON number
+func (x *Decimal) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if len(s) > 0 && s[0] == '"' {
+		var err error
+		if s, err = strconv.Unquote(s); err != nil {
+			return errors.New("wide: invalid JSON decimal " + string(data))
+		}
+	}
+	return x.UnmarshalText([]byte(s))
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface, see ParseDecimal
+func (x *Decimal) UnmarshalText(text []byte) error {
+	s := text
+	neg := false
+	if len(s) > 0 && (s[0] == '-' || s[0] == '+') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+	var mag Uint128
+	var scale uint
+	digits, point := 0, false
+	for _, c := range s {
+		switch {
+		case c == '.' && !point:
+			point = true
+			continue
+		case c < '0' || c > '9':
+			return errors.New("wide: invalid decimal " + strconv.Quote(string(text)))
+		}
+		hi, lo := mag.mulFull(Uint128{lo: 10})
+		mag = lo.Add(Uint128{lo: uint64(c - '0')})
+		if hi.lo != 0 || mag.Lt(lo) || mag.hi > 1<<63 || mag.hi == 1<<63 && (mag.lo != 0 || !neg) {
+			return errors.New("wide: decimal out of range " + strconv.Quote(string(text)))
+		}
+		digits++
+		if point {
+			scale++
+		}
+	}
+	if digits == 0 {
+		return errors.New("wide: invalid decimal " + strconv.Quote(string(text)))
+	}
+	if scale > MaxDecimalScale {
+		return errors.New("wide: decimal scale out of range " + strconv.Quote(string(text)))
+	}
+	*x = Decimal{coef: signed128(mag, neg), scale: scale}
+	return nil
+}
+
+// appendDecimal appends the decimal digits of a Uint128 to dst
+func appendDecimal(dst []byte, x Uint128) []byte {
+	// Peel off 19 digits at a time, which is the most that fits in a uint64
+	var chunks [3]uint64
+	n := 0
+	for x.hi != 0 {
+		var r Uint128
+		x, r = x.DivMod(pow10tab[19])
+		chunks[n] = r.lo
+		n++
+	}
+	dst = strconv.AppendUint(dst, x.lo, 10)
+	for n--; n >= 0; n-- {
+		s := strconv.FormatUint(chunks[n], 10)
+		for i := len(s); i < 19; i++ {
+			dst = append(dst, '0')
+		}
+		dst = append(dst, s...)
+	}
+	return dst
+}
+
+// divRound256 returns the 256-bit (hi, lo) divided by d * 10^k, rounded according to mode, where neg is the sign of the result
+func divRound256(hi, lo, d Uint128, k uint, neg bool, mode RoundingMode) (Uint128, Uint128) {
+	// half compares the discarded fraction with one half, and is maintained exactly through each successive division
+	hi, lo, r := quoRem256(hi, lo, d)
+	half := r.Cmp(d.Sub(r))
+	inexact := r.hi != 0 || r.lo != 0
+	for k > 0 {
+		n := min(k, MaxDecimalScale)
+		k -= n
+		b := pow10tab[n]
+		hi, lo, r = quoRem256(hi, lo, b)
+		// b is even, so the previous fraction only matters when r is exactly b/2
+		if half = r.Cmp(b.RShift()); half == 0 && inexact {
+			half = 1
+		}
+		inexact = inexact || r.hi != 0 || r.lo != 0
+	}
+	if !inexact {
+		half = -1
+	}
+	if mode.roundUp(half, inexact, lo.lo&1 != 0, neg) {
+		if lo = lo.Inc(); lo.hi == 0 && lo.lo == 0 {
+			hi = hi.Inc()
+		}
+	}
+	return hi, lo
+}
+
+// fitsInt128 returns whether the 256-bit magnitude (hi, lo) with the given sign fits in an Int128
+func fitsInt128(hi, lo Uint128, neg bool) bool {
+	// The magnitude of a negative Int128 may be one larger, i.e. 2^127
+	return hi.hi == 0 && hi.lo == 0 && (lo.hi < 1<<(int64Size-1) || neg && lo.hi == 1<<(int64Size-1) && lo.lo == 0)
+}
+
+// quoRem256 returns the 256-bit quotient and the remainder of the 256-bit (hi, lo) divided by d
+//
+// A dividend which fits in 128 bits, as it does for most coefficients, is divided with DivMod alone.
+func quoRem256(hi, lo, d Uint128) (qhi, qlo, r Uint128) {
+	if hi.hi == 0 && hi.lo == 0 {
+		qlo, r = lo.DivMod(d)
+		return Uint128{}, qlo, r
+	}
+	qhi, r = hi.DivMod(d)
+	qlo, r = divMod256(r, lo, d)
+	return qhi, qlo, r
+}
+
+// scaleUp256 returns the 256-bit (hi, lo) multiplied by 10^k, truncated to 256 bits
+func scaleUp256(hi, lo Uint128, k uint) (Uint128, Uint128) {
+	for k > 0 {
+		n := min(k, MaxDecimalScale)
+		k -= n
+		_, h := hi.mulFull(pow10tab[n])
+		hi, lo = lo.mulFull(pow10tab[n])
+		hi = hi.Add(h)
+	}
+	return hi, lo
+}
+
+// signed128 returns the Int128 with magnitude x and the given sign
+//
+// This function overflows silently
+func signed128(x Uint128, neg bool) Int128 {
+	if neg {
+		return x.Int128().Neg()
+	}
+	return x.Int128()
+}
@@ -0,0 +1,313 @@
+package wide
+
+import (
+	"encoding/json"
+	"math/big"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// mustParseDecimal returns the Decimal represented by s, and panics on failure
+func mustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		inp      string
+		expected Decimal
+	}{
+		{"0", Decimal{coef: Int128{hi: 0, lo: 0}, scale: 0}},
+		{"-0.00", Decimal{coef: Int128{hi: 0, lo: 0}, scale: 2}},
+		{"1.50", Decimal{coef: Int128{hi: 0, lo: 150}, scale: 2}},
+		{"+1.5", Decimal{coef: Int128{hi: 0, lo: 15}, scale: 1}},
+		{"-123.456", Decimal{coef: Int128FromInt64(-123456), scale: 3}},
+		{".5", Decimal{coef: Int128{hi: 0, lo: 5}, scale: 1}},
+		{"170141183460469231731687303715884105727", Decimal{coef: Int128{hi: maxInt64, lo: maxUint64}, scale: 0}},
+		{"-1.70141183460469231731687303715884105728", Decimal{coef: Int128{hi: minInt64, lo: 0}, scale: 38}},
+	}
+	for _, test := range tests {
+		result, err := ParseDecimal(test.inp)
+		if err != nil || result != test.expected {
+			t.Errorf("Expected ParseDecimal(%q) == %v, got: %v, %v", test.inp, test.expected, result, err)
+		}
+	}
+}
+
+func TestParseDecimalError(t *testing.T) {
+	tests := []string{
+		"",
+		"-",
+		".",
+		"1.2.3",
+		"1e5",
+		"0x10",
+		" 1",
+		"170141183460469231731687303715884105728",
+		"0.000000000000000000000000000000000000001",
+	}
+	for _, test := range tests {
+		if result, err := ParseDecimal(test); err == nil {
+			t.Errorf("Expected ParseDecimal(%q) to fail, got: %v", test, result)
+		}
+	}
+}
+
+func TestStringDecimal(t *testing.T) {
+	tests := []struct {
+		inp      Decimal
+		expected string
+	}{
+		{Decimal{}, "0"},
+		{NewDecimal(Int128{hi: 0, lo: 0}, 3), "0.000"},
+		{NewDecimal(Int128{hi: 0, lo: 5}, 3), "0.005"},
+		{NewDecimal(Int128FromInt64(-5), 1), "-0.5"},
+		{NewDecimal(Int128FromInt64(-12345), 2), "-123.45"},
+		{NewDecimal(Int128{hi: 1, lo: 0}, 0), "18446744073709551616"},
+		{NewDecimal(Int128{hi: minInt64, lo: 0}, 0), "-170141183460469231731687303715884105728"},
+		{NewDecimal(Int128{hi: maxInt64, lo: maxUint64}, 38), "1.70141183460469231731687303715884105727"},
+	}
+	for _, test := range tests {
+		result := test.inp.String()
+		if result != test.expected {
+			t.Errorf("Expected %v.String() == %s, got: %s", test.inp.coef, test.expected, result)
+		}
+	}
+}
+
+func TestAddSubDecimal(t *testing.T) {
+	tests := []struct {
+		op1, op2 string
+		sum      string
+		diff     string
+	}{
+		{"1.5", "2.25", "3.75", "-0.75"},
+		{"-1", "0.001", "-0.999", "-1.001"},
+		{"100", "100", "200", "0"},
+	}
+	for _, test := range tests {
+		x, y := mustParseDecimal(test.op1), mustParseDecimal(test.op2)
+		if result := x.Add(y).String(); result != test.sum {
+			t.Errorf("Expected %s.Add(%s) == %s, got: %s", test.op1, test.op2, test.sum, result)
+		}
+		if result := x.Sub(y).String(); result != test.diff {
+			t.Errorf("Expected %s.Sub(%s) == %s, got: %s", test.op1, test.op2, test.diff, result)
+		}
+	}
+}
+
+func TestAddSubCheckedDecimal(t *testing.T) {
+	maxDec := NewDecimal(Int128{hi: maxInt64, lo: maxUint64}, 0)
+	minDec := NewDecimal(Int128{hi: minInt64}, 0)
+	one := mustParseDecimal("1")
+	tests := []struct {
+		op1, op2      Decimal
+		sum, diff     string
+		sumOk, diffOk bool
+	}{
+		{mustParseDecimal("1.5"), mustParseDecimal("2.25"), "3.75", "-0.75", true, true},
+		{maxDec, one.Neg(), maxDec.Sub(one).String(), "", true, false},
+		{maxDec, one, "", maxDec.Sub(one).String(), false, true},
+		{minDec, one, minDec.Add(one).String(), "", true, false},
+		{minDec.Add(one), one.Neg(), minDec.String(), minDec.Add(one).Add(one).String(), true, true},
+		{minDec, minDec, "", "0", false, true},
+		{maxDec, mustParseDecimal("0.1"), "", "", false, false},
+		{mustParseDecimal("-0.1"), minDec, "", "", false, false},
+	}
+	for _, test := range tests {
+		op1, op2 := test.op1.String(), test.op2.String()
+		if result, ok := test.op1.AddChecked(test.op2); ok != test.sumOk || ok && result.String() != test.sum {
+			t.Errorf("Expected %s.AddChecked(%s) == (%s, %t), got: (%s, %t)", op1, op2, test.sum, test.sumOk, result, ok)
+		}
+		if result, ok := test.op1.SubChecked(test.op2); ok != test.diffOk || ok && result.String() != test.diff {
+			t.Errorf("Expected %s.SubChecked(%s) == (%s, %t), got: (%s, %t)", op1, op2, test.diff, test.diffOk, result, ok)
+		}
+	}
+}
+
+func TestMulQuoRescaleCheckedDecimal(t *testing.T) {
+	maxDec := NewDecimal(Int128{hi: maxInt64, lo: maxUint64}, 0)
+	minDec := NewDecimal(Int128{hi: minInt64}, 0)
+	one := mustParseDecimal("1")
+	tests := []struct {
+		name     string
+		f        func() (Decimal, bool)
+		expected string
+		ok       bool
+	}{
+		{"1.5.MulChecked(2)", func() (Decimal, bool) {
+			return mustParseDecimal("1.5").MulChecked(mustParseDecimal("2"), 1, RoundHalfEven)
+		}, "3.0", true},
+		{"min.MulChecked(1)", func() (Decimal, bool) { return minDec.MulChecked(one, 0, RoundHalfEven) }, minDec.String(), true},
+		{"min.MulChecked(-1)", func() (Decimal, bool) { return minDec.MulChecked(one.Neg(), 0, RoundHalfEven) }, "", false},
+		{"max.MulChecked(1, 1)", func() (Decimal, bool) { return maxDec.MulChecked(one, 1, RoundHalfEven) }, "", false},
+		{"1.QuoChecked(3)", func() (Decimal, bool) { return one.QuoChecked(mustParseDecimal("3"), 2, RoundHalfEven) }, "0.33", true},
+		{"max.QuoChecked(0.1)", func() (Decimal, bool) { return maxDec.QuoChecked(mustParseDecimal("0.1"), 0, RoundHalfEven) }, "", false},
+		{"min.QuoChecked(-1)", func() (Decimal, bool) { return minDec.QuoChecked(one.Neg(), 0, RoundHalfEven) }, "", false},
+		{"1.25.RescaleChecked(1)", func() (Decimal, bool) { return mustParseDecimal("1.25").RescaleChecked(1, RoundHalfEven) }, "1.2", true},
+		{"1.RescaleChecked(38)", func() (Decimal, bool) { return one.RescaleChecked(MaxDecimalScale, RoundHalfEven) }, "1." + strings.Repeat("0", MaxDecimalScale), true},
+		{"max.RescaleChecked(1)", func() (Decimal, bool) { return maxDec.RescaleChecked(1, RoundHalfEven) }, "", false},
+	}
+	for _, test := range tests {
+		if result, ok := test.f(); ok != test.ok || ok && result.String() != test.expected {
+			t.Errorf("Expected %s == (%s, %t), got: (%s, %t)", test.name, test.expected, test.ok, result, ok)
+		}
+	}
+}
+
+func TestCmpDecimal(t *testing.T) {
+	tests := []struct {
+		op1, op2 string
+		expected int
+	}{
+		{"1.50", "1.5", 0},
+		{"1.51", "1.5", 1},
+		{"-1.51", "-1.5", -1},
+		{"-1", "0.00", -1},
+		{"0", "-0.00", 0},
+		{"170141183460469231731687303715884105727", "1.70141183460469231731687303715884105727", 1},
+	}
+	for _, test := range tests {
+		result := mustParseDecimal(test.op1).Cmp(mustParseDecimal(test.op2))
+		if result != test.expected {
+			t.Errorf("Expected %s.Cmp(%s) == %v, got: %v", test.op1, test.op2, test.expected, result)
+		}
+	}
+}
+
+func TestRescaleDecimal(t *testing.T) {
+	modes := []RoundingMode{RoundUp, RoundDown, RoundCeiling, RoundFloor, RoundHalfUp, RoundHalfEven}
+	tests := []struct {
+		inp      string
+		expected []string // in the order of modes
+	}{
+		{"5.5", []string{"6", "5", "6", "5", "6", "6"}},
+		{"2.5", []string{"3", "2", "3", "2", "3", "2"}},
+		{"1.6", []string{"2", "1", "2", "1", "2", "2"}},
+		{"1.1", []string{"2", "1", "2", "1", "1", "1"}},
+		{"1.0", []string{"1", "1", "1", "1", "1", "1"}},
+		{"-1.0", []string{"-1", "-1", "-1", "-1", "-1", "-1"}},
+		{"-1.1", []string{"-2", "-1", "-1", "-2", "-1", "-1"}},
+		{"-1.6", []string{"-2", "-1", "-1", "-2", "-2", "-2"}},
+		{"-2.5", []string{"-3", "-2", "-2", "-3", "-3", "-2"}},
+		{"-5.5", []string{"-6", "-5", "-5", "-6", "-6", "-6"}},
+		{"2.5000000000000000000000000000000000001", []string{"3", "2", "3", "2", "3", "3"}},
+	}
+	for _, test := range tests {
+		for i, mode := range modes {
+			result := mustParseDecimal(test.inp).Rescale(0, mode).String()
+			if result != test.expected[i] {
+				t.Errorf("Expected %s.Rescale(0, %v) == %s, got: %s", test.inp, mode, test.expected[i], result)
+			}
+		}
+	}
+	if result := mustParseDecimal("-1.5").Rescale(3, RoundDown).String(); result != "-1.500" {
+		t.Errorf("Expected -1.5.Rescale(3) == -1.500, got: %s", result)
+	}
+}
+
+func TestMulDecimal(t *testing.T) {
+	tests := []struct {
+		op1, op2 string
+		scale    uint
+		mode     RoundingMode
+		expected string
+	}{
+		{"1.5", "2.5", 2, RoundHalfEven, "3.75"},
+		{"1.5", "2.5", 1, RoundHalfEven, "3.8"},
+		{"1.5", "2.5", 1, RoundDown, "3.7"},
+		{"-1.5", "2.5", 1, RoundFloor, "-3.8"},
+		{"-1.5", "2.5", 1, RoundCeiling, "-3.7"},
+		{"0.15", "0.5", 5, RoundHalfEven, "0.07500"},
+		// The exact product needs more than 128 bits
+		{"123456789012345678.9012345678901234567", "9876543210987654.321098765432109876", 2, RoundHalfEven, "1219326311370217952261850327338667.82"},
+	}
+	for _, test := range tests {
+		result := mustParseDecimal(test.op1).Mul(mustParseDecimal(test.op2), test.scale, test.mode).String()
+		if result != test.expected {
+			t.Errorf("Expected %s.Mul(%s, %v, %v) == %s, got: %s", test.op1, test.op2, test.scale, test.mode, test.expected, result)
+		}
+	}
+}
+
+func TestQuoDecimal(t *testing.T) {
+	tests := []struct {
+		op1, op2 string
+		scale    uint
+		mode     RoundingMode
+		expected string
+	}{
+		{"1", "3", 5, RoundHalfEven, "0.33333"},
+		{"2", "3", 5, RoundHalfEven, "0.66667"},
+		{"2", "3", 5, RoundDown, "0.66666"},
+		{"-2", "3", 0, RoundHalfEven, "-1"},
+		{"10.00", "4", 0, RoundHalfEven, "2"},
+		{"10.00", "4", 0, RoundHalfUp, "3"},
+		{"1", "0.0001", 2, RoundHalfEven, "10000.00"},
+		{"0.0001", "1000", 2, RoundUp, "0.01"},
+		{"0.0001", "1000", 2, RoundHalfEven, "0.00"},
+	}
+	for _, test := range tests {
+		result := mustParseDecimal(test.op1).Quo(mustParseDecimal(test.op2), test.scale, test.mode).String()
+		if result != test.expected {
+			t.Errorf("Expected %s.Quo(%s, %v, %v) == %s, got: %s", test.op1, test.op2, test.scale, test.mode, test.expected, result)
+		}
+	}
+}
+
+func TestQuoRandDecimal(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		x := NewDecimal(randNumTheoryInt128(r).RShiftN(uint(r.Intn(64))), uint(r.Intn(20)))
+		y := NewDecimal(randNumTheoryInt128(r).RShiftN(uint(r.Intn(64))).Or(Int128{lo: 1}), uint(r.Intn(20)))
+		scale := uint(r.Intn(20))
+		// Truncated division, cross-checked against big.Int
+		num := new(big.Int).Mul(bigInt128(x.coef), new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(y.scale+scale)), nil))
+		den := new(big.Int).Mul(bigInt128(y.coef), new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(x.scale)), nil))
+		expected := new(big.Int).Quo(num, den)
+		if !expected.IsInt64() && expected.BitLen() > 126 {
+			continue
+		}
+		result := x.Quo(y, scale, RoundDown)
+		if bigInt128(result.coef).Cmp(expected) != 0 || result.scale != scale {
+			t.Errorf("Expected %s.Quo(%s, %v) == %se-%v, got: %s", x, y, scale, expected, scale, result)
+		}
+	}
+}
+
+func TestJSONDecimal(t *testing.T) {
+	type ledger struct {
+		Amount Decimal `json:"amount"`
+	}
+	b, err := json.Marshal(ledger{Amount: mustParseDecimal("-12.340")})
+	if err != nil || string(b) != `{"amount":"-12.340"}` {
+		t.Errorf("Expected json.Marshal == %s, got: %s, %v", `{"amount":"-12.340"}`, b, err)
+	}
+	for _, inp := range []string{`{"amount":"-12.340"}`, `{"amount":-12.340}`} {
+		var l ledger
+		if err := json.Unmarshal([]byte(inp), &l); err != nil || l.Amount != mustParseDecimal("-12.340") {
+			t.Errorf("Expected json.Unmarshal(%s) == -12.340, got: %s, %v", inp, l.Amount, err)
+		}
+	}
+	var l ledger
+	if err := json.Unmarshal([]byte(`{"amount":"twelve"}`), &l); err == nil {
+		t.Errorf("Expected json.Unmarshal to fail for an invalid decimal")
+	}
+}
+
+func TestTextDecimal(t *testing.T) {
+	x := mustParseDecimal("3.14159")
+	b, err := x.MarshalText()
+	if err != nil || string(b) != "3.14159" {
+		t.Errorf("Expected MarshalText() == 3.14159, got: %s, %v", b, err)
+	}
+	var y Decimal
+	if err := y.UnmarshalText(b); err != nil || y != x {
+		t.Errorf("Expected UnmarshalText(%s) == %s, got: %s, %v", b, x, y, err)
+	}
+}
@@ -0,0 +1,437 @@
+package wide
+
+import (
+	"errors"
+	"math"
+	"math/bits"
+	"strconv"
+)
+
+// Fixed64x64 is a signed binary fixed-point number with 64 integer bits and 64 fractional bits (i.e. Q64.64)
+//
+// Every operation is computed with integer arithmetic only, so that results are identical on all platforms. Results which are
+// not exact are rounded to the nearest multiple of 2^-64 (ties to even) unless documented otherwise. The zero value is 0.
+type Fixed64x64 struct {
+	raw Int128
+}
+
+// ln2Fixed is ln(2) with 128 fractional bits, truncated, and ln2FixedLo holds the next 64 bits
+var (
+	ln2Fixed   = Uint128{hi: 0xb17217f7d1cf79ab, lo: 0xc9e3b39803f2f6af}
+	ln2FixedLo = uint64(0x40f343267298b62d)
+)
+
+// log2eFixed is log2(e) with 20 fractional bits, rounded
+const log2eFixed = 1512775
+
+// maxFixed64x64 is the largest Fixed64x64, 2^63 - 2^-64
+var maxFixed64x64 = Fixed64x64{raw: Int128{hi: maxInt64, lo: maxUint64}}
+
+// NewFixed64x64 returns the Fixed64x64 hi + lo * 2^-64, i.e. the integer part and the fractional bits
+func NewFixed64x64(hi int64, lo uint64) Fixed64x64 {
+	return Fixed64x64{raw: Int128{hi: hi, lo: lo}}
+}
+
+// Fixed64x64FromBits returns the Fixed64x64 x * 2^-64
+func Fixed64x64FromBits(x Int128) Fixed64x64 {
+	return Fixed64x64{raw: x}
+}
+
+// Fixed64x64FromFloat64 returns the nearest Fixed64x64 to a float64
+//
+// Fixed64x64FromFloat64 panics if f is NaN, infinite, or not in the range [-2^63, 2^63).
+func Fixed64x64FromFloat64(f float64) Fixed64x64 {
+	if math.IsNaN(f) || f < -(1<<63) || f >= 1<<63 {
+		panic("fixed-point value out of range")
+	}
+	frac, exp := math.Frexp(math.Abs(f))
+	mant := Uint128{lo: uint64(frac * (1 << 53))}
+	// f = mant * 2^(exp-53), and the raw value is f * 2^64
+	var mag Uint128
+	if shift := exp + 11; shift >= 0 {
+		mag = mant.LShiftN(uint(shift))
+	} else {
+		mag = roundShift(mant, uint(-shift), false, RoundHalfEven)
+	}
+	return Fixed64x64{raw: signed128(mag, f < 0)}
+}
+
+// Fixed64x64FromInt64 returns the Fixed64x64 equal to an int64
+func Fixed64x64FromInt64(x int64) Fixed64x64 {
+	return Fixed64x64{raw: Int128{hi: x, lo: 0}}
+}
+
+// ParseFixed64x64 parses a string of the form "[+-]digits[.digits]" as the nearest Fixed64x64
+//
+// At most MaxDecimalScale fractional digits are accepted, which is more than String ever produces.
+func ParseFixed64x64(s string) (Fixed64x64, error) {
+	var x Fixed64x64
+	if err := x.UnmarshalText([]byte(s)); err != nil {
+		return Fixed64x64{}, err
+	}
+	return x, nil
+}
+
+// Abs returns the absolute value of a Fixed64x64
+//
+// This function overflows silently
+func (x Fixed64x64) Abs() Fixed64x64 {
+	return Fixed64x64{raw: x.raw.Abs()}
+}
+
+// Add returns the sum of two Fixed64x64's
+//
+// This function overflows silently
+func (x Fixed64x64) Add(y Fixed64x64) Fixed64x64 {
+	return Fixed64x64{raw: x.raw.Add(y.raw)}
+}
+
+// Bits returns the underlying Int128 of a Fixed64x64, i.e. x * 2^64
+func (x Fixed64x64) Bits() Int128 {
+	return x.raw
+}
+
+// Ceil returns the least integer greater than or equal to a Fixed64x64
+//
+// This function overflows silently
+func (x Fixed64x64) Ceil() Fixed64x64 {
+	if x.raw.lo == 0 {
+		return x
+	}
+	return Fixed64x64{raw: Int128{hi: x.raw.hi + 1, lo: 0}}
+}
+
+// Cmp compares x and y and returns:
+//
+//   -1 if x <  y
+//    0 if x == y
+//   +1 if x >  y
+func (x Fixed64x64) Cmp(y Fixed64x64) int {
+	return x.raw.Cmp(y.raw)
+}
+
+// Div returns the quotient of two Fixed64x64's
+//
+// Div panics on division by 0. This function overflows silently
+func (x Fixed64x64) Div(y Fixed64x64) Fixed64x64 {
+	if y.raw.Sign() == 0 {
+		panic("runtime error: integer divide by zero")
+	}
+	neg := x.raw.IsNeg() != y.raw.IsNeg()
+	m := x.raw.Abs().Uint128()
+	_, q := divRound256(Uint128{lo: m.hi}, Uint128{hi: m.lo}, y.raw.Abs().Uint128(), 0, neg, RoundHalfEven)
+	return Fixed64x64{raw: signed128(q, neg)}
+}
+
+// Exp returns e^x
+//
+// The error is less than 2^-64 + e^x * 2^-120, which is within one unit in the last place unless x > 38. Results larger than the
+// largest Fixed64x64 saturate.
+func (x Fixed64x64) Exp() Fixed64x64 {
+	switch {
+	case x.raw.hi >= 44: // e^44 > 2^63
+		return maxFixed64x64
+	case x.raw.hi < -46: // e^-46 < 2^-65
+		return Fixed64x64{}
+	}
+	// Reduce to x = k*ln(2) + r, where 0 <= r < ln(2), and r is kept with 128 fractional bits
+	k := (x.raw.hi<<32 | int64(x.raw.lo>>32)) * log2eFixed >> 52
+	kAbs := uint64(k)
+	if k < 0 {
+		kAbs = uint64(-k)
+	}
+	kHi, _ := bits.Mul64(kAbs, ln2FixedLo)
+	kLn2 := Uint128{lo: kAbs}.Mul(ln2Fixed).Add(Uint128{lo: kHi})
+	r := Uint128{hi: x.raw.lo, lo: 0}
+	if k < 0 {
+		r = r.Add(kLn2)
+	} else {
+		r = r.Sub(kLn2)
+	}
+	// The estimate of k may be off by one when x/ln(2) is close to an integer
+	switch {
+	case r.hi >= 0xf<<60:
+		k--
+		r = r.Add(ln2Fixed)
+	case r.Gte(ln2Fixed):
+		k++
+		r = r.Sub(ln2Fixed)
+	}
+	// e^r - 1, as a Taylor series with 128 fractional bits
+	sum, term := r, r
+	for n := uint64(2); ; n++ {
+		term, _ = term.mulFull(r)
+		if term = term.Div(Uint128{lo: n}); term.hi == 0 && term.lo == 0 {
+			break
+		}
+		sum = sum.Add(term)
+	}
+	// e^x = 2^k * (1 + sum*2^-128)
+	switch {
+	case k >= 63:
+		return maxFixed64x64
+	case k < -65:
+		return Fixed64x64{}
+	case k == -65:
+		if sum.hi == 0 && sum.lo == 0 {
+			return Fixed64x64{}
+		}
+		return Fixed64x64{raw: Int128{hi: 0, lo: 1}}
+	}
+	z := Uint128{lo: 1}.LShiftN(uint(64 + k)).Add(roundShift(sum, uint(64-k), false, RoundHalfEven))
+	if z.hi>>63 != 0 {
+		return maxFixed64x64
+	}
+	return Fixed64x64{raw: z.Int128()}
+}
+
+// Float64 returns the nearest float64 to a Fixed64x64
+func (x Fixed64x64) Float64() float64 {
+	m := x.raw.Abs().Uint128()
+	var f float64
+	if m.hi == 0 {
+		f = math.Ldexp(float64(m.lo), -64)
+	} else {
+		// Keep the top 64 bits, with a sticky bit so that the conversion to float64 still rounds correctly
+		shift := m.Len() - 64
+		top := m.RShiftN(shift)
+		if !m.Eq(top.LShiftN(shift)) {
+			top.lo |= 1
+		}
+		f = math.Ldexp(float64(top.lo), int(shift)-64)
+	}
+	if x.raw.IsNeg() {
+		return -f
+	}
+	return f
+}
+
+// Floor returns the greatest integer less than or equal to a Fixed64x64
+func (x Fixed64x64) Floor() Fixed64x64 {
+	return Fixed64x64{raw: Int128{hi: x.raw.hi, lo: 0}}
+}
+
+// Log returns the natural logarithm of a Fixed64x64
+//
+// The error is less than 2^-64, i.e. one unit in the last place. Log panics if x is not positive.
+func (x Fixed64x64) Log() Fixed64x64 {
+	switch x.raw.Sign() {
+	case -1:
+		panic("logarithm of negative number")
+	case 0:
+		panic("logarithm of zero")
+	}
+	// Normalize to x = m * 2^e, where 1 <= m < 2 and m is kept with 126 fractional bits
+	m := x.raw.Uint128()
+	e := int(m.Len()) - 1
+	m = m.LShiftN(uint(126 - e))
+	one := Uint128{hi: 1 << 62, lo: 0}
+	// ln(m) = 2*atanh(s), where s = (m-1)/(m+1) < 1/3, as a series with 128 fractional bits
+	s, _ := divMod256(m.Sub(one), Uint128{}, m.Add(one))
+	s2, _ := s.mulFull(s)
+	sum, term := s, s
+	for n := uint64(3); ; n += 2 {
+		term, _ = term.mulFull(s2)
+		t := term.Div(Uint128{lo: n})
+		if t.hi == 0 && t.lo == 0 {
+			break
+		}
+		sum = sum.Add(t)
+	}
+	// ln(x) = (e-64)*ln(2) + ln(m), with 120 fractional bits
+	z := Int128FromInt64(int64(e - 64)).Mul(ln2Fixed.RShiftN(8).Int128()).Add(sum.RShiftN(7).Int128())
+	neg := z.IsNeg()
+	return Fixed64x64{raw: signed128(roundShift(z.Abs().Uint128(), 56, false, RoundHalfEven), neg)}
+}
+
+// MarshalText implements the encoding.TextMarshaler interface
+func (x Fixed64x64) MarshalText() ([]byte, error) {
+	return []byte(x.String()), nil
+}
+
+// Mul returns the product of two Fixed64x64's
+//
+// The product is computed exactly before rounding. This function overflows silently
+func (x Fixed64x64) Mul(y Fixed64x64) Fixed64x64 {
+	neg := x.raw.IsNeg() != y.raw.IsNeg()
+	hi, lo := x.raw.Abs().Uint128().mulFull(y.raw.Abs().Uint128())
+	z := Uint128{hi: hi.lo, lo: lo.hi}
+	if RoundHalfEven.roundUp(cmp64(lo.lo, 1<<63), lo.lo != 0, z.lo&1 != 0, neg) {
+		z = z.Inc()
+	}
+	return Fixed64x64{raw: signed128(z, neg)}
+}
+
+// Neg returns the additive inverse of a Fixed64x64
+//
+// This function overflows silently
+func (x Fixed64x64) Neg() Fixed64x64 {
+	return Fixed64x64{raw: x.raw.Neg()}
+}
+
+// Round returns the nearest integer to a Fixed64x64, rounding ties away from zero
+//
+// This function overflows silently
+func (x Fixed64x64) Round() Fixed64x64 {
+	if x.raw.lo > 1<<63 || x.raw.lo == 1<<63 && x.raw.hi >= 0 {
+		return Fixed64x64{raw: Int128{hi: x.raw.hi + 1, lo: 0}}
+	}
+	return x.Floor()
+}
+
+// Sign returns the sign of a Fixed64x64
+func (x Fixed64x64) Sign() int {
+	return x.raw.Sign()
+}
+
+// Sqrt returns the square root of a Fixed64x64
+//
+// The result is correctly rounded. Sqrt panics if x is negative.
+func (x Fixed64x64) Sqrt() Fixed64x64 {
+	if x.raw.IsNeg() {
+		panic("square root of negative number")
+	}
+	// The raw result is the square root of raw(x) * 2^64
+	m := x.raw.Uint128()
+	root, rem := sqrt256(m.RShiftN(64), m.LShiftN(64))
+	// The exact root is at least root + 1/2 iff rem >= root + 1/4
+	if rem.Gt(root) {
+		root = root.Inc()
+	}
+	return Fixed64x64{raw: root.Int128()}
+}
+
+// String returns the shortest decimal representation of a Fixed64x64 which ParseFixed64x64 parses as the same value
+func (x Fixed64x64) String() string {
+	var buf []byte
+	if x.raw.IsNeg() {
+		buf = append(buf, '-')
+	}
+	m := x.raw.Abs().Uint128()
+	buf = strconv.AppendUint(buf, m.hi, 10)
+	if m.lo == 0 {
+		return string(buf)
+	}
+	// 20 digits always suffice, since 10^-20 is less than half of 2^-64
+	for n := uint(1); ; n++ {
+		hi, lo := Uint128{lo: m.lo}.mulFull(pow10tab[n])
+		d := Uint128{hi: hi.lo, lo: lo.hi}
+		if RoundHalfEven.roundUp(cmp64(lo.lo, 1<<63), lo.lo != 0, d.lo&1 != 0, false) {
+			d = d.Inc()
+		}
+		if _, f := divRound256(d.RShiftN(64), d.LShiftN(64), pow10tab[n], 0, false, RoundHalfEven); f.hi == 0 && f.lo == m.lo {
+			digits := appendDecimal(nil, d)
+			buf = append(buf, '.')
+			for i := uint(len(digits)); i < n; i++ {
+				buf = append(buf, '0')
+			}
+			for len(digits) > 1 && digits[len(digits)-1] == '0' {
+				digits = digits[:len(digits)-1]
+			}
+			return string(append(buf, digits...))
+		}
+	}
+}
+
+// Sub returns the difference of two Fixed64x64's
+//
+// This function overflows silently
+func (x Fixed64x64) Sub(y Fixed64x64) Fixed64x64 {
+	return Fixed64x64{raw: x.raw.Sub(y.raw)}
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface, see ParseFixed64x64
+func (x *Fixed64x64) UnmarshalText(text []byte) error {
+	s := text
+	neg := false
+	if len(s) > 0 && (s[0] == '-' || s[0] == '+') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+	var ip uint64
+	var frac Uint128
+	var scale uint
+	digits, point := 0, false
+	for _, c := range s {
+		switch {
+		case c == '.' && !point:
+			point = true
+			continue
+		case c < '0' || c > '9':
+			return errors.New("wide: invalid fixed-point number " + strconv.Quote(string(text)))
+		}
+		if point {
+			if scale++; scale > MaxDecimalScale {
+				return errors.New("wide: too many fractional digits " + strconv.Quote(string(text)))
+			}
+			frac = frac.Mul(Uint128{lo: 10}).Add(Uint128{lo: uint64(c - '0')})
+		} else {
+			hi, lo := bits.Mul64(ip, 10)
+			ip = lo + uint64(c-'0')
+			if hi != 0 || ip < lo || ip > 1<<63 {
+				return errors.New("wide: fixed-point number out of range " + strconv.Quote(string(text)))
+			}
+		}
+		digits++
+	}
+	if digits == 0 {
+		return errors.New("wide: invalid fixed-point number " + strconv.Quote(string(text)))
+	}
+	// The fractional part rounds to frac * 2^64 / 10^scale, which may carry into the integer part
+	fhi, flo := divRound256(frac.RShiftN(64), frac.LShiftN(64), pow10tab[scale], 0, neg, RoundHalfEven)
+	m := Uint128{hi: ip, lo: 0}.Add(flo).Add(Uint128{hi: fhi.lo, lo: 0})
+	if m.hi > 1<<63 || m.hi == 1<<63 && (m.lo != 0 || !neg) {
+		return errors.New("wide: fixed-point number out of range " + strconv.Quote(string(text)))
+	}
+	*x = Fixed64x64{raw: signed128(m, neg)}
+	return nil
+}
+
+// cmp64 compares two uint64's, returning -1, 0, or +1
+func cmp64(x, y uint64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// roundShift returns x * 2^-n, rounded to an integer according to mode, where neg is the sign of the result
+func roundShift(x Uint128, n uint, neg bool, mode RoundingMode) Uint128 {
+	switch {
+	case n == 0:
+		return x
+	case n > int128Size:
+		// The result is less than one half
+		if mode.roundUp(-1, x.hi != 0 || x.lo != 0, false, neg) {
+			return Uint128{lo: 1}
+		}
+		return Uint128{}
+	}
+	q := x.RShiftN(n)
+	r := x.Sub(q.LShiftN(n))
+	half := Uint128{lo: 1}.LShiftN(n - 1)
+	if mode.roundUp(r.Cmp(half), r.hi != 0 || r.lo != 0, q.lo&1 != 0, neg) {
+		q = q.Inc()
+	}
+	return q
+}
+
+// sqrt256 returns the integer square root of the 256-bit (hi, lo) and the remainder, which requires hi < 2^122
+func sqrt256(hi, lo Uint128) (root, rem Uint128) {
+	// Two bits at a time, where the remainder never exceeds 2*root
+	for i := 0; i < 128; i++ {
+		rem = rem.LShiftN(2).Or(Uint128{lo: hi.hi >> 62})
+		hi = hi.LShiftN(2).Or(Uint128{lo: lo.hi >> 62})
+		lo = lo.LShiftN(2)
+		root = root.LShift()
+		if trial := root.LShift().Or(Uint128{lo: 1}); rem.Gte(trial) {
+			rem = rem.Sub(trial)
+			root = root.Or(Uint128{lo: 1})
+		}
+	}
+	return root, rem
+}
@@ -0,0 +1,319 @@
+package wide
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+// bigRoundRat returns the nearest integer to num/den (ties to even), for a positive den
+func bigRoundRat(num, den *big.Int) *big.Int {
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	c := new(big.Int).Abs(new(big.Int).Lsh(r, 1)).Cmp(den)
+	if c > 0 || c == 0 && q.Bit(0) != 0 {
+		if num.Sign() < 0 {
+			return q.Sub(q, big.NewInt(1))
+		}
+		return q.Add(q, big.NewInt(1))
+	}
+	return q
+}
+
+// bigExp returns e^x with the precision of x
+func bigExp(x *big.Float) *big.Float {
+	prec := x.Prec()
+	// e^x = (e^(x/2^32))^(2^32), where the Taylor series of e^(x/2^32) converges quickly
+	y := new(big.Float).SetPrec(prec).SetMantExp(x, -32)
+	sum := new(big.Float).SetPrec(prec).SetInt64(1)
+	term := new(big.Float).SetPrec(prec).SetInt64(1)
+	for n := int64(1); n < 100; n++ {
+		term.Mul(term, y)
+		term.Quo(term, new(big.Float).SetInt64(n))
+		sum.Add(sum, term)
+	}
+	for i := 0; i < 32; i++ {
+		sum.Mul(sum, sum)
+	}
+	return sum
+}
+
+// bigLog returns the natural logarithm of a positive x with the precision of x
+func bigLog(x *big.Float) *big.Float {
+	prec := x.Prec()
+	f, _ := x.Float64()
+	y := new(big.Float).SetPrec(prec).SetFloat64(math.Log(f))
+	// Newton's method, y = y + 2*(x - e^y)/(x + e^y)
+	for i := 0; i < 5; i++ {
+		ey := bigExp(y)
+		num := new(big.Float).SetPrec(prec).Sub(x, ey)
+		den := new(big.Float).SetPrec(prec).Add(x, ey)
+		y.Add(y, num.Quo(num, den).SetMantExp(num, 1))
+	}
+	return y
+}
+
+// bigFixed64x64 returns the exact value of a Fixed64x64 as a big.Float
+func bigFixed64x64(x Fixed64x64, prec uint) *big.Float {
+	f := new(big.Float).SetPrec(prec).SetInt(bigInt128(x.raw))
+	return f.SetMantExp(f, -64)
+}
+
+// randFixed64x64 returns a random Fixed64x64 with a random magnitude and sign
+func randFixed64x64(r *rand.Rand) Fixed64x64 {
+	return Fixed64x64{raw: randNumTheoryInt128(r)}
+}
+
+func TestStringFixed64x64(t *testing.T) {
+	tests := []struct {
+		inp      Fixed64x64
+		expected string
+	}{
+		{Fixed64x64{}, "0"},
+		{Fixed64x64FromInt64(-7), "-7"},
+		{NewFixed64x64(1, 1<<63), "1.5"},
+		{NewFixed64x64(-2, 1<<63), "-1.5"},
+		{NewFixed64x64(0, 1<<62), "0.25"},
+		{NewFixed64x64(0, 0x1999999999999999), "0.09999999999999999997"},
+		{NewFixed64x64(0, 0x199999999999999a), "0.1"},
+		{NewFixed64x64(0, 1), "0.00000000000000000005"},
+		{NewFixed64x64(0, maxUint64), "0.99999999999999999995"},
+		{NewFixed64x64(maxInt64, maxUint64), "9223372036854775807.99999999999999999995"},
+		{NewFixed64x64(minInt64, 0), "-9223372036854775808"},
+	}
+	for _, test := range tests {
+		result := test.inp.String()
+		if result != test.expected {
+			t.Errorf("Expected %s.String() == %s, got: %s", test.inp.raw, test.expected, result)
+		}
+	}
+}
+
+func TestParseFixed64x64(t *testing.T) {
+	tests := []struct {
+		inp      string
+		expected Fixed64x64
+	}{
+		{"0", Fixed64x64{}},
+		{"-0.0", Fixed64x64{}},
+		{"+1.5", NewFixed64x64(1, 1<<63)},
+		{"-1.5", NewFixed64x64(-2, 1<<63)},
+		{".1", NewFixed64x64(0, 0x199999999999999a)},
+		{"0.00000000000000000002", NewFixed64x64(0, 0)},
+		{"0.00000000000000000003", NewFixed64x64(0, 1)},
+		{"0.99999999999999999999999999999999999999", NewFixed64x64(1, 0)},
+		{"9223372036854775807.99999999999999999995", NewFixed64x64(maxInt64, maxUint64)},
+		{"-9223372036854775808", NewFixed64x64(minInt64, 0)},
+	}
+	for _, test := range tests {
+		result, err := ParseFixed64x64(test.inp)
+		if err != nil || result != test.expected {
+			t.Errorf("Expected ParseFixed64x64(%q) == %s, got: %s, %v", test.inp, test.expected, result, err)
+		}
+	}
+	for _, inp := range []string{"", "-", ".", "1.2.3", "1e5", "9223372036854775808", "-9223372036854775808.1", "9223372036854775807.99999999999999999998",
+		"0.000000000000000000000000000000000000001"} {
+		if result, err := ParseFixed64x64(inp); err == nil {
+			t.Errorf("Expected ParseFixed64x64(%q) to fail, got: %s", inp, result)
+		}
+	}
+}
+
+func TestStringRandFixed64x64(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		x := randFixed64x64(r)
+		s := x.String()
+		if result, err := ParseFixed64x64(s); err != nil || result != x {
+			t.Errorf("Expected ParseFixed64x64(%s.String()) == %s, got: %s, %v", x.raw, s, result, err)
+		}
+	}
+}
+
+func TestFloorCeilRoundFixed64x64(t *testing.T) {
+	tests := []struct {
+		inp                string
+		floor, ceil, round string
+	}{
+		{"0", "0", "0", "0"},
+		{"2", "2", "2", "2"},
+		{"2.25", "2", "3", "2"},
+		{"2.5", "2", "3", "3"},
+		{"2.75", "2", "3", "3"},
+		{"-2.25", "-3", "-2", "-2"},
+		{"-2.5", "-3", "-2", "-3"},
+		{"-2.75", "-3", "-2", "-3"},
+	}
+	for _, test := range tests {
+		x, _ := ParseFixed64x64(test.inp)
+		if result := x.Floor().String(); result != test.floor {
+			t.Errorf("Expected %s.Floor() == %s, got: %s", test.inp, test.floor, result)
+		}
+		if result := x.Ceil().String(); result != test.ceil {
+			t.Errorf("Expected %s.Ceil() == %s, got: %s", test.inp, test.ceil, result)
+		}
+		if result := x.Round().String(); result != test.round {
+			t.Errorf("Expected %s.Round() == %s, got: %s", test.inp, test.round, result)
+		}
+	}
+}
+
+func TestMulDivFixed64x64(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		x, y := randFixed64x64(r), randFixed64x64(r)
+		bx, by := bigInt128(x.raw), bigInt128(y.raw)
+		if expected := bigRoundRat(new(big.Int).Mul(bx, by), new(big.Int).Lsh(big.NewInt(1), 64)); expected.BitLen() < 127 {
+			if result := bigInt128(x.Mul(y).raw); result.Cmp(expected) != 0 {
+				t.Errorf("Expected %s.Mul(%s) == %s, got: %s", x.raw, y.raw, expected, result)
+			}
+		}
+		if y.raw.Sign() == 0 {
+			continue
+		}
+		if by.Sign() < 0 {
+			bx.Neg(bx)
+			by.Neg(by)
+		}
+		if expected := bigRoundRat(new(big.Int).Lsh(bx, 64), by); expected.BitLen() < 127 {
+			if result := bigInt128(x.Div(y).raw); result.Cmp(expected) != 0 {
+				t.Errorf("Expected %s.Div(%s) == %s, got: %s", x.raw, y.raw, expected, result)
+			}
+		}
+	}
+}
+
+func TestDivFixed64x64Panic(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Expected Div by 0 to panic")
+		}
+	}()
+	Fixed64x64FromInt64(1).Div(Fixed64x64{})
+}
+
+func TestFloat64Fixed64x64(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		x := randFixed64x64(r)
+		expected, _ := bigFixed64x64(x, 256).Float64()
+		if result := x.Float64(); result != expected {
+			t.Errorf("Expected %s.Float64() == %v, got: %v", x.raw, expected, result)
+		}
+		f := math.Ldexp(r.Float64(), r.Intn(130)-66)
+		if r.Intn(2) == 0 {
+			f = -f
+		}
+		bf := new(big.Float).SetFloat64(f)
+		n, _ := bf.SetMantExp(bf, 64+200).Int(nil)
+		if want := bigRoundRat(n, new(big.Int).Lsh(big.NewInt(1), 200)); want.BitLen() < 127 {
+			if result := bigInt128(Fixed64x64FromFloat64(f).raw); result.Cmp(want) != 0 {
+				t.Errorf("Expected Fixed64x64FromFloat64(%v) == %s, got: %s", f, want, result)
+			}
+		}
+	}
+	if result := Fixed64x64FromFloat64(-(1 << 63)); result != NewFixed64x64(minInt64, 0) {
+		t.Errorf("Expected Fixed64x64FromFloat64(-2^63) == %s, got: %s", NewFixed64x64(minInt64, 0), result)
+	}
+	for _, f := range []float64{math.NaN(), math.Inf(1), 1 << 63} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("Expected Fixed64x64FromFloat64(%v) to panic", f)
+				}
+			}()
+			Fixed64x64FromFloat64(f)
+		}()
+	}
+}
+
+func TestSqrtFixed64x64(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		x := randFixed64x64(r).Abs()
+		n := new(big.Int).Lsh(bigInt128(x.raw), 64)
+		expected := new(big.Int).Sqrt(n)
+		// Round up iff (2*root + 1)^2 <= 4n
+		odd := new(big.Int).Add(new(big.Int).Lsh(expected, 1), big.NewInt(1))
+		if odd.Mul(odd, odd).Cmp(n.Lsh(n, 2)) <= 0 {
+			expected.Add(expected, big.NewInt(1))
+		}
+		if result := bigInt128(x.Sqrt().raw); result.Cmp(expected) != 0 {
+			t.Errorf("Expected %s.Sqrt() == %s, got: %s", x.raw, expected, result)
+		}
+	}
+	if result := Fixed64x64FromInt64(2).Sqrt().String(); result != "1.4142135623730950488" {
+		t.Errorf("Expected 2.Sqrt() == 1.4142135623730950488, got: %s", result)
+	}
+}
+
+func TestExpFixed64x64(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	tol := new(big.Float).SetMantExp(big.NewFloat(1), -56)
+	for i := 0; i < 500; i++ {
+		x := Fixed64x64{raw: Int128{hi: int64(r.Intn(96)) - 48, lo: r.Uint64()}}
+		if i%4 == 0 {
+			x.raw.hi = int64(r.Intn(2)) - 1
+		}
+		result := bigInt128(x.Exp().raw)
+		exact := bigExp(bigFixed64x64(x, 512))
+		exact.SetMantExp(exact, 64)
+		if maxRaw := new(big.Float).SetInt(bigInt128(maxFixed64x64.raw)); exact.Cmp(maxRaw) > 0 {
+			if result.Cmp(bigInt128(maxFixed64x64.raw)) != 0 {
+				t.Errorf("Expected %s.Exp() to saturate, got: %s", x, result)
+			}
+			continue
+		}
+		// |result - exact| <= 1 + exact*2^-56, in units of 2^-64
+		diff := new(big.Float).Sub(new(big.Float).SetInt(result), exact)
+		bound := new(big.Float).Mul(exact, tol)
+		if diff.Abs(diff).Cmp(bound.Add(bound, big.NewFloat(1))) > 0 {
+			t.Errorf("Expected %s.Exp() == %.2f, got: %s", x, exact, result)
+		}
+	}
+	tests := []struct {
+		inp      Fixed64x64
+		expected string
+	}{
+		{Fixed64x64{}, "1"},
+		{Fixed64x64FromInt64(1), "2.7182818284590452354"},
+		{Fixed64x64FromInt64(-1), "0.3678794411714423216"},
+		{Fixed64x64FromInt64(-45), "0.00000000000000000005"},
+		{Fixed64x64FromInt64(-46), "0"},
+	}
+	for _, test := range tests {
+		if result := test.inp.Exp().String(); result != test.expected {
+			t.Errorf("Expected %s.Exp() == %s, got: %s", test.inp, test.expected, result)
+		}
+	}
+}
+
+func TestLogFixed64x64(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		x := randFixed64x64(r).Abs()
+		if x.raw.Sign() == 0 {
+			continue
+		}
+		result := bigInt128(x.Log().raw)
+		exact := bigLog(bigFixed64x64(x, 512))
+		exact.SetMantExp(exact, 64)
+		diff := new(big.Float).Sub(new(big.Float).SetInt(result), exact)
+		if diff.Abs(diff).Cmp(big.NewFloat(1)) >= 0 {
+			t.Errorf("Expected %s.Log() == %.2f, got: %s", x, exact, result)
+		}
+	}
+	if result := Fixed64x64FromInt64(1).Log(); result.Sign() != 0 {
+		t.Errorf("Expected 1.Log() == 0, got: %s", result)
+	}
+	for _, x := range []Fixed64x64{{}, Fixed64x64FromInt64(-1)} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("Expected %s.Log() to panic", x)
+				}
+			}()
+			x.Log()
+		}()
+	}
+}
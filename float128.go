@@ -0,0 +1,533 @@
+package wide
+
+import (
+	"math"
+	"math/big"
+)
+
+// Float128 is an IEEE 754 binary128 (quadruple precision) floating-point number, stored in the bits of a Uint128
+//
+// Arithmetic is implemented in software, and every result is correctly rounded according to the given RoundingMode, where
+// RoundHalfEven and RoundHalfUp are the IEEE roundTiesToEven and roundTiesToAway modes. NaN results are quiet NaN's, and no
+// exception flags are kept. The zero value is +0.
+type Float128 struct {
+	bits Uint128
+}
+
+// Binary128 parameters
+const (
+	float128Bias   = 16383
+	float128MaxExp = 0x7fff // biased exponent of infinities and NaN's
+	float128MinExp = -16382 // exponent of the smallest normal number
+	float128Prec   = 113    // precision in bits, including the implicit bit
+)
+
+// float128NaN is the default quiet NaN
+var float128NaN = Float128{bits: Uint128{hi: 0x7fff8 << 44, lo: 0}}
+
+// Float128FromBigFloat returns a big.Float rounded to a Float128
+func Float128FromBigFloat(f *big.Float, mode RoundingMode) Float128 {
+	switch {
+	case f.IsInf():
+		return float128Inf(f.Signbit())
+	case f.Sign() == 0:
+		return float128Zero(f.Signbit())
+	}
+	// Truncate the magnitude to 128 bits, with a sticky bit
+	exp := f.MantExp(nil)
+	s := new(big.Float).SetMantExp(f, int128Size-exp)
+	i, acc := s.Abs(s).Int(nil)
+	m := Uint128FromBigInt(i)
+	if acc != big.Exact {
+		m.lo |= 1
+	}
+	return packFloat128(f.Signbit(), exp-int128Size, m, mode)
+}
+
+// Float128FromBits returns the Float128 with the given IEEE 754 binary128 encoding
+func Float128FromBits(b Uint128) Float128 {
+	return Float128{bits: b}
+}
+
+// Float128FromFloat64 returns the Float128 equal to a float64
+//
+// The payloads of NaN's are preserved.
+func Float128FromFloat64(f float64) Float128 {
+	b := math.Float64bits(f)
+	neg := b>>63 != 0
+	e, frac := int(b>>52&0x7ff), b&(1<<52-1)
+	switch {
+	case e == 0x7ff:
+		return Float128{bits: Uint128{hi: b>>63<<63 | float128MaxExp<<48 | frac>>4, lo: frac << 60}}
+	case e == 0 && frac == 0:
+		return float128Zero(neg)
+	case e != 0:
+		frac |= 1 << 52
+	}
+	return packFloat128(neg, max(e, 1)-1075, Uint128{lo: frac}, RoundHalfEven)
+}
+
+// Abs returns the absolute value of a Float128
+func (x Float128) Abs() Float128 {
+	x.bits.hi &^= 1 << 63
+	return x
+}
+
+// Add returns the sum of two Float128's, rounded according to mode
+func (x Float128) Add(y Float128, mode RoundingMode) Float128 {
+	switch {
+	case x.IsNaN() || y.IsNaN():
+		return firstNaN(x, y)
+	case x.IsInf(0):
+		if y.IsInf(0) && x.Signbit() != y.Signbit() {
+			return float128NaN
+		}
+		return x
+	case y.IsInf(0):
+		return y
+	case x.isZero() && y.isZero():
+		return float128Zero(x.Signbit() && y.Signbit() || x.Signbit() != y.Signbit() && mode == RoundFloor)
+	case x.isZero():
+		return y
+	case y.isZero():
+		return x
+	}
+	xneg, xexp, xm := x.unpack()
+	yneg, yexp, ym := y.unpack()
+	if xexp < yexp || xexp == yexp && xm.Lt(ym) {
+		xneg, xexp, xm, yneg, yexp, ym = yneg, yexp, ym, xneg, xexp, xm
+	}
+	// Align to the larger magnitude, keeping 13 guard bits and a sticky bit
+	xm, ym = xm.LShiftN(13), ym.LShiftN(13)
+	if d := xexp - yexp; d >= int128Size {
+		ym = Uint128{lo: 1}
+	} else if s := ym.RShiftN(uint(d)); !ym.Eq(s.LShiftN(uint(d))) {
+		ym = s.Or(Uint128{lo: 1})
+	} else {
+		ym = s
+	}
+	var m Uint128
+	if xneg == yneg {
+		m = xm.Add(ym)
+	} else if m = xm.Sub(ym); m.hi == 0 && m.lo == 0 {
+		return float128Zero(mode == RoundFloor)
+	}
+	return packFloat128(xneg, xexp-13, m, mode)
+}
+
+// BigFloat returns a Float128 as a big.Float with 113 bits of precision
+//
+// BigFloat panics if x is NaN.
+func (x Float128) BigFloat() *big.Float {
+	switch {
+	case x.IsNaN():
+		panic("conversion of NaN to big.Float")
+	case x.IsInf(0):
+		return new(big.Float).SetInf(x.Signbit())
+	case x.isZero():
+		f := new(big.Float).SetPrec(float128Prec)
+		if x.Signbit() {
+			f.Neg(f)
+		}
+		return f
+	}
+	neg, exp, m := x.unpack()
+	f := new(big.Float).SetPrec(float128Prec).SetInt(m.bigInt())
+	f.SetMantExp(f, exp)
+	if neg {
+		f.Neg(f)
+	}
+	return f
+}
+
+// Bits returns the IEEE 754 binary128 encoding of a Float128
+func (x Float128) Bits() Uint128 {
+	return x.bits
+}
+
+// Div returns the quotient of two Float128's, rounded according to mode
+func (x Float128) Div(y Float128, mode RoundingMode) Float128 {
+	neg := x.Signbit() != y.Signbit()
+	switch {
+	case x.IsNaN() || y.IsNaN():
+		return firstNaN(x, y)
+	case x.IsInf(0) && y.IsInf(0), x.isZero() && y.isZero():
+		return float128NaN
+	case x.IsInf(0), y.isZero():
+		return float128Inf(neg)
+	case y.IsInf(0), x.isZero():
+		return float128Zero(neg)
+	}
+	_, xexp, xm := x.unpack()
+	_, yexp, ym := y.unpack()
+	// xm * 2^127 / ym has 127 or 128 bits
+	q, r := divMod256(xm.RShift(), xm.LShiftN(int128Size-1), ym)
+	if r.hi != 0 || r.lo != 0 {
+		q.lo |= 1
+	}
+	return packFloat128(neg, xexp-yexp-(int128Size-1), q, mode)
+}
+
+// Eq returns whether x is equal to y
+//
+// NaN's are not equal to anything, and +0 is equal to -0.
+func (x Float128) Eq(y Float128) bool {
+	switch {
+	case x.IsNaN() || y.IsNaN():
+		return false
+	case x.isZero() && y.isZero():
+		return true
+	default:
+		return x.bits.Eq(y.bits)
+	}
+}
+
+// Float64 returns a Float128 rounded to a float64
+//
+// The payloads of NaN's are truncated, and signaling NaN's become quiet NaN's.
+func (x Float128) Float64(mode RoundingMode) float64 {
+	sign := x.bits.hi >> 63 << 63
+	switch {
+	case x.IsNaN():
+		return math.Float64frombits(sign | 0x7ff<<52 | 1<<51 | x.bits.hi<<4&(1<<52-1) | x.bits.lo>>60)
+	case x.IsInf(0):
+		return math.Float64frombits(sign | 0x7ff<<52)
+	case x.isZero():
+		return math.Float64frombits(sign)
+	}
+	neg, exp, m := x.unpack()
+	e, q := roundFloat(neg, exp, m, mode, 53, -1022)
+	b := uint64(e)<<52 + q.lo
+	if e >= 0x7ff || b>>52 >= 0x7ff {
+		if mode.roundUp(1, true, true, neg) {
+			b = 0x7ff << 52
+		} else {
+			b = 0x7fe<<52 | 1<<52 - 1
+		}
+	}
+	return math.Float64frombits(sign | b)
+}
+
+// FMA returns x * y + z, computed exactly and then rounded once according to mode
+func (x Float128) FMA(y, z Float128, mode RoundingMode) Float128 {
+	pneg := x.Signbit() != y.Signbit()
+	switch {
+	case x.IsNaN() || y.IsNaN() || z.IsNaN():
+		return firstNaN(x, y, z)
+	case x.IsInf(0) && y.isZero(), x.isZero() && y.IsInf(0):
+		return float128NaN
+	case x.IsInf(0) || y.IsInf(0):
+		if z.IsInf(0) && z.Signbit() != pneg {
+			return float128NaN
+		}
+		return float128Inf(pneg)
+	case z.IsInf(0):
+		return z
+	case x.isZero() || y.isZero():
+		return float128Zero(pneg).Add(z, mode)
+	case z.isZero():
+		return x.Mul(y, mode)
+	}
+	_, xexp, xm := x.unpack()
+	_, yexp, ym := y.unpack()
+	zneg, zexp, zm := z.unpack()
+	// Normalize both the exact product and z so that their leading bits are at bit 253 of a 256-bit integer
+	phi, plo := xm.mulFull(ym)
+	s := 254 - len256(phi, plo)
+	phi, plo = phi.LShiftN(s).Or(plo.RShiftN(int128Size-s)), plo.LShiftN(s)
+	pexp := xexp + yexp - int(s)
+	zhi, zlo := zm.LShiftN(253-int128Size-(float128Prec-1)), Uint128{}
+	zexp -= 253 - (float128Prec - 1)
+	if pexp < zexp || pexp == zexp && cmp256(phi, plo, zhi, zlo) < 0 {
+		pneg, pexp, phi, plo, zneg, zexp, zhi, zlo = zneg, zexp, zhi, zlo, pneg, pexp, phi, plo
+	}
+	zhi, zlo = rShiftSticky256(zhi, zlo, uint(min(pexp-zexp, 256)))
+	var hi, lo Uint128
+	if pneg == zneg {
+		hi, lo = add256(phi, plo, zhi, zlo)
+	} else if hi, lo = sub256(phi, plo, zhi, zlo); hi.hi == 0 && hi.lo == 0 && lo.hi == 0 && lo.lo == 0 {
+		return float128Zero(mode == RoundFloor)
+	}
+	// Keep the leading 128 bits, with a sticky bit
+	if n := len256(hi, lo); n > int128Size {
+		hi, lo = rShiftSticky256(hi, lo, n-int128Size)
+		pexp += int(n - int128Size)
+	}
+	return packFloat128(pneg, pexp, lo, mode)
+}
+
+// Gt returns whether x is greater than y, which is false if either is NaN
+func (x Float128) Gt(y Float128) bool {
+	return y.Lt(x)
+}
+
+// Gte returns whether x is greater than or equal to y, which is false if either is NaN
+func (x Float128) Gte(y Float128) bool {
+	return y.Lte(x)
+}
+
+// IsInf reports whether x is an infinity, according to sign
+//
+// If sign > 0, IsInf reports whether x is positive infinity. If sign < 0, IsInf reports whether x is negative infinity. If sign
+// == 0, IsInf reports whether x is either infinity.
+func (x Float128) IsInf(sign int) bool {
+	if x.bits.hi&^(1<<63) != float128MaxExp<<48 || x.bits.lo != 0 {
+		return false
+	}
+	return sign == 0 || sign > 0 != x.Signbit()
+}
+
+// IsNaN reports whether x is a NaN
+func (x Float128) IsNaN() bool {
+	return x.bits.hi>>48&float128MaxExp == float128MaxExp && (x.bits.hi&(1<<48-1) != 0 || x.bits.lo != 0)
+}
+
+// isZero reports whether x is +0 or -0
+func (x Float128) isZero() bool {
+	return x.bits.hi&^(1<<63) == 0 && x.bits.lo == 0
+}
+
+// Lt returns whether x is less than y, which is false if either is NaN
+func (x Float128) Lt(y Float128) bool {
+	switch {
+	case x.IsNaN() || y.IsNaN(), x.isZero() && y.isZero():
+		return false
+	case x.Signbit() != y.Signbit():
+		return x.Signbit()
+	case x.Signbit():
+		return x.bits.Gt(y.bits)
+	default:
+		return x.bits.Lt(y.bits)
+	}
+}
+
+// Lte returns whether x is less than or equal to y, which is false if either is NaN
+func (x Float128) Lte(y Float128) bool {
+	return x.Lt(y) || x.Eq(y)
+}
+
+// Mul returns the product of two Float128's, rounded according to mode
+func (x Float128) Mul(y Float128, mode RoundingMode) Float128 {
+	neg := x.Signbit() != y.Signbit()
+	switch {
+	case x.IsNaN() || y.IsNaN():
+		return firstNaN(x, y)
+	case x.IsInf(0) && y.isZero(), x.isZero() && y.IsInf(0):
+		return float128NaN
+	case x.IsInf(0) || y.IsInf(0):
+		return float128Inf(neg)
+	case x.isZero() || y.isZero():
+		return float128Zero(neg)
+	}
+	_, xexp, xm := x.unpack()
+	_, yexp, ym := y.unpack()
+	// The exact product has 225 or 226 bits, of which the leading 128 are kept, with a sticky bit
+	hi, lo := xm.mulFull(ym)
+	hi, lo = rShiftSticky256(hi, lo, 98)
+	return packFloat128(neg, xexp+yexp+98, lo, mode)
+}
+
+// Neg returns a Float128 with the opposite sign
+func (x Float128) Neg() Float128 {
+	x.bits.hi ^= 1 << 63
+	return x
+}
+
+// quiet returns a NaN with the quiet bit set
+func (x Float128) quiet() Float128 {
+	x.bits.hi |= 1 << 47
+	return x
+}
+
+// Signbit reports whether x is negative or negative zero
+func (x Float128) Signbit() bool {
+	return x.bits.hi>>63 != 0
+}
+
+// Sqrt returns the square root of a Float128, rounded according to mode
+//
+// The square root of -0 is -0, and the square root of any other negative number is NaN.
+func (x Float128) Sqrt(mode RoundingMode) Float128 {
+	switch {
+	case x.IsNaN():
+		return x.quiet()
+	case x.isZero():
+		return x
+	case x.Signbit():
+		return float128NaN
+	case x.IsInf(0):
+		return x
+	}
+	_, exp, m := x.unpack()
+	if exp&1 != 0 {
+		m = m.LShift()
+		exp--
+	}
+	// m * 2^136 has 249 or 250 bits, so its root has 125 bits
+	root, rem := sqrt256(m.LShiftN(8), Uint128{})
+	if rem.hi != 0 || rem.lo != 0 {
+		root.lo |= 1
+	}
+	return packFloat128(false, (exp-136)/2, root, mode)
+}
+
+// String returns the shortest decimal representation of a Float128 which rounds to the same value at 113 bits of precision
+func (x Float128) String() string {
+	if x.IsNaN() {
+		return "NaN"
+	}
+	return x.BigFloat().Text('g', -1)
+}
+
+// Sub returns the difference of two Float128's, rounded according to mode
+func (x Float128) Sub(y Float128, mode RoundingMode) Float128 {
+	if y.IsNaN() {
+		return firstNaN(x, y)
+	}
+	return x.Add(y.Neg(), mode)
+}
+
+// unpack returns the sign, exponent and significand of a finite, non-zero Float128, where x = ±m * 2^exp and m has exactly
+// 113 bits, even if x is subnormal
+func (x Float128) unpack() (neg bool, exp int, m Uint128) {
+	neg = x.Signbit()
+	e := int(x.bits.hi >> 48 & float128MaxExp)
+	m = Uint128{hi: x.bits.hi & (1<<48 - 1), lo: x.bits.lo}
+	if e == 0 {
+		s := float128Prec - m.Len()
+		return neg, float128MinExp - (float128Prec - 1) - int(s), m.LShiftN(s)
+	}
+	m.hi |= 1 << 48
+	return neg, e - float128Bias - (float128Prec - 1), m
+}
+
+// add256 returns the sum of two 256-bit integers
+//
+// This function overflows silently
+func add256(xhi, xlo, yhi, ylo Uint128) (hi, lo Uint128) {
+	lo = xlo.Add(ylo)
+	hi = xhi.Add(yhi)
+	if lo.Lt(xlo) {
+		hi = hi.Inc()
+	}
+	return hi, lo
+}
+
+// cmp256 compares two 256-bit integers, returning -1, 0, or +1
+func cmp256(xhi, xlo, yhi, ylo Uint128) int {
+	if c := xhi.Cmp(yhi); c != 0 {
+		return c
+	}
+	return xlo.Cmp(ylo)
+}
+
+// firstNaN returns the first NaN among its arguments, as a quiet NaN
+func firstNaN(xs ...Float128) Float128 {
+	for _, x := range xs {
+		if x.IsNaN() {
+			return x.quiet()
+		}
+	}
+	return float128NaN
+}
+
+// float128Inf returns +Inf or -Inf
+func float128Inf(neg bool) Float128 {
+	z := float128Zero(neg)
+	z.bits.hi |= float128MaxExp << 48
+	return z
+}
+
+// float128Zero returns +0 or -0
+func float128Zero(neg bool) (z Float128) {
+	if neg {
+		z.bits.hi = 1 << 63
+	}
+	return z
+}
+
+// len256 returns the minimum number of bits required to represent a 256-bit integer
+func len256(hi, lo Uint128) uint {
+	if hi.hi != 0 || hi.lo != 0 {
+		return hi.Len() + int128Size
+	}
+	return lo.Len()
+}
+
+// packFloat128 returns ±m * 2^exp rounded to a Float128 according to mode
+//
+// The least significant bit of m may be a sticky bit (i.e. the logical or of all discarded bits), in which case m must have at
+// least 115 significant bits.
+func packFloat128(neg bool, exp int, m Uint128, mode RoundingMode) Float128 {
+	e, q := roundFloat(neg, exp, m, mode, float128Prec, float128MinExp)
+	var z Float128
+	if e < float128MaxExp {
+		z.bits = Uint128{hi: uint64(e) << 48, lo: 0}.Add(q)
+	}
+	if e >= float128MaxExp || z.bits.hi>>48 >= float128MaxExp {
+		// Overflow rounds to infinity, unless the rounding direction is toward zero
+		if mode.roundUp(1, true, true, neg) {
+			z.bits = Uint128{hi: float128MaxExp << 48, lo: 0}
+		} else {
+			z.bits = Uint128{hi: float128MaxExp<<48 - 1, lo: maxUint64}
+		}
+	}
+	if neg {
+		z.bits.hi |= 1 << 63
+	}
+	return z
+}
+
+// roundFloat returns ±m * 2^exp rounded to a binary floating-point number with prec bits of precision, where emin is the
+// exponent of the smallest normal number
+//
+// The result is encoded as e<<(prec-1) + q, where e+1 is the biased exponent of a normal result, so that a significand which
+// rounds up to the next power of two carries into the exponent. Subnormal results have e == 0 and q < 2^(prec-1).
+func roundFloat(neg bool, exp int, m Uint128, mode RoundingMode, prec uint, emin int) (e int, q Uint128) {
+	n := int(m.Len())
+	shift := n - int(prec)
+	if e = exp + n - 1 - emin; e < 0 {
+		// Subnormal, so fewer bits of precision are available
+		shift -= e
+		e = 0
+	}
+	if shift <= 0 {
+		return e, m.LShiftN(uint(-shift))
+	}
+	return e, roundShift(m, uint(shift), neg, mode)
+}
+
+// rShiftSticky256 returns a 256-bit integer right-shifted by n, with the discarded bits or'ed into the least significant bit
+func rShiftSticky256(hi, lo Uint128, n uint) (Uint128, Uint128) {
+	var zhi, zlo Uint128
+	var sticky bool
+	switch {
+	case n == 0:
+		return hi, lo
+	case n >= 2*int128Size:
+		sticky = hi.hi != 0 || hi.lo != 0 || lo.hi != 0 || lo.lo != 0
+	case n >= int128Size:
+		zlo = hi.RShiftN(n - int128Size)
+		sticky = lo.hi != 0 || lo.lo != 0 || !hi.Eq(zlo.LShiftN(n-int128Size))
+	default:
+		zhi = hi.RShiftN(n)
+		zlo = lo.RShiftN(n).Or(hi.LShiftN(int128Size - n))
+		sticky = !lo.Eq(lo.RShiftN(n).LShiftN(n))
+	}
+	if sticky {
+		zlo.lo |= 1
+	}
+	return zhi, zlo
+}
+
+// sub256 returns the difference of two 256-bit integers
+//
+// This function overflows silently
+func sub256(xhi, xlo, yhi, ylo Uint128) (hi, lo Uint128) {
+	lo = xlo.Sub(ylo)
+	hi = xhi.Sub(yhi)
+	if xlo.Lt(ylo) {
+		hi = hi.Dec()
+	}
+	return hi, lo
+}
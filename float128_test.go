@@ -0,0 +1,328 @@
+package wide
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+// float128Modes are all rounding modes, with their big.Float equivalents
+var float128Modes = []struct {
+	mode RoundingMode
+	big  big.RoundingMode
+}{
+	{RoundHalfEven, big.ToNearestEven},
+	{RoundHalfUp, big.ToNearestAway},
+	{RoundDown, big.ToZero},
+	{RoundUp, big.AwayFromZero},
+	{RoundCeiling, big.ToPositiveInf},
+	{RoundFloor, big.ToNegativeInf},
+}
+
+// float128Max is the largest finite Float128
+var float128Max = Float128{bits: Uint128{hi: 0x7ffeffffffffffff, lo: maxUint64}}
+
+// bigFloat128 returns the result of op rounded to binary128 (including subnormals and overflow) according to mode, or nil if
+// the result is too small for big.Float to round it the same way
+func bigFloat128(op func(z *big.Float), mode RoundingMode, bigMode big.RoundingMode) *big.Float {
+	z := new(big.Float).SetPrec(1000).SetMode(big.ToZero)
+	op(z)
+	prec := float128Prec
+	if z.Sign() != 0 {
+		// Subnormal results have fewer bits of precision
+		if e := z.MantExp(nil); e-1 < float128MinExp {
+			if prec = e - float128MinExp + float128Prec - 1; prec <= 0 {
+				return nil
+			}
+		}
+	}
+	z = new(big.Float).SetPrec(uint(prec)).SetMode(bigMode)
+	op(z)
+	if z.MantExp(nil) > float128Bias+1 {
+		if mode.roundUp(1, true, true, z.Signbit()) {
+			return new(big.Float).SetInf(z.Signbit())
+		}
+		if z.Signbit() {
+			return float128Max.Neg().BigFloat()
+		}
+		return float128Max.BigFloat()
+	}
+	return z
+}
+
+// sameFloat128 reports whether a non-NaN Float128 has the same value and sign as a big.Float
+func sameFloat128(x Float128, f *big.Float) bool {
+	return !x.IsNaN() && x.BigFloat().Cmp(f) == 0 && x.Signbit() == f.Signbit()
+}
+
+// randFloat128 returns a random finite Float128, which is often near the subnormal or overflow thresholds
+func randFloat128(r *rand.Rand) Float128 {
+	var e uint64
+	switch r.Intn(8) {
+	case 0:
+		e = 0
+	case 1:
+		e = uint64(r.Intn(200))
+	case 2:
+		e = float128MaxExp - 1 - uint64(r.Intn(200))
+	default:
+		e = float128Bias - 150 + uint64(r.Intn(300))
+	}
+	x := Float128{bits: Uint128{hi: r.Uint64()>>63<<63 | e<<48 | r.Uint64()>>16, lo: r.Uint64()}}
+	if r.Intn(4) == 0 {
+		// Few significant bits, so that results are often exact or ties
+		x.bits = x.bits.RShiftN(64).LShiftN(64)
+	}
+	return x
+}
+
+func TestFloat64Float128(t *testing.T) {
+	tests := []struct {
+		inp      float64
+		expected Uint128
+	}{
+		{0, Uint128{hi: 0, lo: 0}},
+		{math.Copysign(0, -1), Uint128{hi: 0x8000000000000000, lo: 0}},
+		{1, Uint128{hi: 0x3fff000000000000, lo: 0}},
+		{-2, Uint128{hi: 0xc000000000000000, lo: 0}},
+		{0.1, Uint128{hi: 0x3ffb999999999999, lo: 0xa000000000000000}},
+		{math.MaxFloat64, Uint128{hi: 0x43feffffffffffff, lo: 0xf000000000000000}},
+		{math.SmallestNonzeroFloat64, Uint128{hi: 0x3bcd000000000000, lo: 0}},
+		{math.Inf(1), Uint128{hi: 0x7fff000000000000, lo: 0}},
+		{math.Inf(-1), Uint128{hi: 0xffff000000000000, lo: 0}},
+		{math.NaN(), Uint128{hi: 0x7fff800000000000, lo: 0x1000000000000000}},
+	}
+	for _, test := range tests {
+		result := Float128FromFloat64(test.inp)
+		if !result.Bits().Eq(test.expected) {
+			t.Errorf("Expected Float128FromFloat64(%v) == %s, got: %s", test.inp, test.expected, result.Bits())
+		}
+		if f := result.Float64(RoundHalfEven); math.Float64bits(f) != math.Float64bits(test.inp) {
+			t.Errorf("Expected %s.Float64() == %v, got: %v", result.Bits(), test.inp, f)
+		}
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		x := randFloat128(r)
+		for _, m := range float128Modes {
+			expected := new(big.Float).SetPrec(53).SetMode(m.big).Set(x.BigFloat())
+			if e := expected.MantExp(nil); e-1 < -1022 || e > 1024 {
+				// big.Float has neither subnormals nor overflow
+				continue
+			}
+			want, _ := expected.Float64()
+			if result := x.Float64(m.mode); result != want {
+				t.Errorf("Expected %s.Float64(%v) == %v, got: %v", x, m.mode, want, result)
+			}
+		}
+	}
+	if result := float128Max.Float64(RoundHalfEven); !math.IsInf(result, 1) {
+		t.Errorf("Expected %s.Float64() == +Inf, got: %v", float128Max, result)
+	}
+	if result := float128Max.Neg().Float64(RoundDown); result != -math.MaxFloat64 {
+		t.Errorf("Expected %s.Float64(RoundDown) == %v, got: %v", float128Max.Neg(), -math.MaxFloat64, result)
+	}
+	if result := Float128FromFloat64(math.SmallestNonzeroFloat64).Div(Float128FromFloat64(3), RoundUp).Float64(RoundUp); result != math.SmallestNonzeroFloat64 {
+		t.Errorf("Expected 2^-1074/3 rounded up == 2^-1074, got: %v", result)
+	}
+}
+
+func TestBigFloatFloat128(t *testing.T) {
+	tests := []struct {
+		inp      string
+		expected Uint128
+	}{
+		{"1", Uint128{hi: 0x3fff000000000000, lo: 0}},
+		{"0.1", Uint128{hi: 0x3ffb999999999999, lo: 0x999999999999999a}},
+		{"-3.14159265358979323846264338327950288", Uint128{hi: 0xc000921fb54442d1, lo: 0x8469898cc51701b8}},
+		{"1.18973149535723176508575932662800702e4932", Uint128{hi: 0x7ffeffffffffffff, lo: maxUint64}},
+		{"6.47517511943802511092443895822764655e-4966", Uint128{hi: 0, lo: 1}},
+		{"1e5000", Uint128{hi: 0x7fff000000000000, lo: 0}},
+		{"1e-5000", Uint128{hi: 0, lo: 0}},
+	}
+	for _, test := range tests {
+		f, _, _ := big.ParseFloat(test.inp, 10, 1000, big.ToNearestEven)
+		result := Float128FromBigFloat(f, RoundHalfEven)
+		if !result.Bits().Eq(test.expected) {
+			t.Errorf("Expected Float128FromBigFloat(%s) == %s, got: %s", test.inp, test.expected, result.Bits())
+		}
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		f := new(big.Float).SetPrec(200).SetInt(bigUint128(Uint128{hi: r.Uint64(), lo: r.Uint64()}))
+		f.SetMantExp(f, r.Intn(2*float128Bias+400)-float128Bias-400)
+		for _, m := range float128Modes {
+			result := Float128FromBigFloat(f, m.mode)
+			if expected := bigFloat128(func(z *big.Float) { z.Set(f) }, m.mode, m.big); expected != nil && !sameFloat128(result, expected) {
+				t.Errorf("Expected Float128FromBigFloat(%v, %v) == %v, got: %s", f, m.mode, expected, result)
+			}
+		}
+	}
+}
+
+func TestArithFloat128(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 3000; i++ {
+		x, y := randFloat128(r), randFloat128(r)
+		if i%3 == 0 {
+			// Operands of similar magnitude, so that Add and Sub cancel
+			y.bits.hi = y.bits.hi&^(float128MaxExp<<48) | x.bits.hi&(float128MaxExp<<48)
+		}
+		bx, by := x.BigFloat(), y.BigFloat()
+		ops := []struct {
+			name   string
+			result func(RoundingMode) Float128
+			op     func(z *big.Float)
+		}{
+			{"Add", func(m RoundingMode) Float128 { return x.Add(y, m) }, func(z *big.Float) { z.Add(bx, by) }},
+			{"Sub", func(m RoundingMode) Float128 { return x.Sub(y, m) }, func(z *big.Float) { z.Sub(bx, by) }},
+			{"Mul", func(m RoundingMode) Float128 { return x.Mul(y, m) }, func(z *big.Float) { z.Mul(bx, by) }},
+			{"Div", func(m RoundingMode) Float128 { return x.Div(y, m) }, func(z *big.Float) { z.Quo(bx, by) }},
+		}
+		for _, op := range ops {
+			for _, m := range float128Modes {
+				result := op.result(m.mode)
+				if expected := bigFloat128(op.op, m.mode, m.big); expected != nil && !sameFloat128(result, expected) {
+					t.Errorf("Expected %s.%s(%s, %v) == %v, got: %s", x, op.name, y, m.mode, expected, result)
+				}
+			}
+		}
+	}
+}
+
+func TestFMAFloat128(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 3000; i++ {
+		x, y, z := randFloat128(r), randFloat128(r), randFloat128(r)
+		if p := x.Mul(y, RoundHalfEven); i%2 == 0 && !p.IsInf(0) {
+			// z close to -x*y, so that the sum cancels
+			z = p.Neg()
+			z.bits.lo ^= r.Uint64() >> uint(r.Intn(64))
+		}
+		bx, by, bz := x.BigFloat(), y.BigFloat(), z.BigFloat()
+		for _, m := range float128Modes {
+			result := x.FMA(y, z, m.mode)
+			expected := bigFloat128(func(f *big.Float) {
+				p := new(big.Float).SetPrec(2*float128Prec).Mul(bx, by)
+				f.Add(p, bz)
+			}, m.mode, m.big)
+			if expected != nil && !sameFloat128(result, expected) {
+				t.Errorf("Expected %s.FMA(%s, %s, %v) == %v, got: %s", x, y, z, m.mode, expected, result)
+			}
+		}
+	}
+}
+
+func TestSqrtFloat128(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 3000; i++ {
+		x := randFloat128(r).Abs()
+		if x.isZero() {
+			continue
+		}
+		// The exact root, truncated to 400 bits with a sticky bit, rounds the same as the exact root
+		_, exp, m := x.unpack()
+		n := bigUint128(m)
+		if exp&1 != 0 {
+			n.Lsh(n, 1)
+			exp--
+		}
+		n.Lsh(n, 800)
+		root := new(big.Int).Sqrt(n)
+		if new(big.Int).Mul(root, root).Cmp(n) != 0 {
+			root.Lsh(root, 1).SetBit(root, 0, 1)
+			exp -= 2
+		}
+		exact := new(big.Float).SetInt(root)
+		exact.SetMantExp(exact, (exp-800)/2)
+		for _, m := range float128Modes {
+			result := x.Sqrt(m.mode)
+			if expected := bigFloat128(func(z *big.Float) { z.Set(exact) }, m.mode, m.big); !sameFloat128(result, expected) {
+				t.Errorf("Expected %s.Sqrt(%v) == %v, got: %s", x, m.mode, expected, result)
+			}
+		}
+	}
+}
+
+func TestSpecialFloat128(t *testing.T) {
+	one, two := Float128FromFloat64(1), Float128FromFloat64(2)
+	inf, zero := Float128FromFloat64(math.Inf(1)), Float128{}
+	negZero, nan := zero.Neg(), Float128FromFloat64(math.NaN())
+	snan := Float128{bits: Uint128{hi: 0x7fff000000000000, lo: 1}}
+	tests := []struct {
+		name     string
+		result   Float128
+		expected Float128
+	}{
+		{"Inf + -Inf", inf.Add(inf.Neg(), RoundHalfEven), float128NaN},
+		{"Inf - 1", inf.Sub(one, RoundHalfEven), inf},
+		{"1 - 1", one.Sub(one, RoundHalfEven), zero},
+		{"1 - 1 rounded down", one.Sub(one, RoundFloor), negZero},
+		{"-0 + -0", negZero.Add(negZero, RoundHalfEven), negZero},
+		{"-0 + 0", negZero.Add(zero, RoundHalfEven), zero},
+		{"0 * Inf", zero.Mul(inf, RoundHalfEven), float128NaN},
+		{"-0 * 2", negZero.Mul(two, RoundHalfEven), negZero},
+		{"1 / 0", one.Div(zero, RoundHalfEven), inf},
+		{"1 / -0", one.Div(negZero, RoundHalfEven), inf.Neg()},
+		{"0 / 0", zero.Div(zero, RoundHalfEven), float128NaN},
+		{"1 / Inf", one.Div(inf, RoundHalfEven), zero},
+		{"sqrt(-0)", negZero.Sqrt(RoundHalfEven), negZero},
+		{"sqrt(-1)", one.Neg().Sqrt(RoundHalfEven), float128NaN},
+		{"sqrt(Inf)", inf.Sqrt(RoundHalfEven), inf},
+		{"sqrt(4)", Float128FromFloat64(4).Sqrt(RoundHalfEven), two},
+		{"Inf * 0 + 1", inf.FMA(zero, one, RoundHalfEven), float128NaN},
+		{"Inf * 1 + -Inf", inf.FMA(one, inf.Neg(), RoundHalfEven), float128NaN},
+		{"0 * 1 + -0", zero.FMA(one, negZero, RoundHalfEven), zero},
+		{"-0 * 1 + -0", negZero.FMA(one, negZero, RoundHalfEven), negZero},
+		{"1 * 1 + -1", one.FMA(one, one.Neg(), RoundFloor), negZero},
+		{"NaN + 1", nan.Add(one, RoundHalfEven), nan},
+		{"sNaN + 1", snan.Add(one, RoundHalfEven), snan.quiet()},
+		{"1 * sNaN", one.Mul(snan, RoundHalfEven), snan.quiet()},
+		{"max + max", float128Max.Add(float128Max, RoundHalfEven), inf},
+		{"max + max rounded down", float128Max.Add(float128Max, RoundDown), float128Max},
+		{"-max - max rounded up", float128Max.Neg().Sub(float128Max, RoundCeiling), float128Max.Neg()},
+	}
+	for _, test := range tests {
+		if !test.result.Bits().Eq(test.expected.Bits()) {
+			t.Errorf("Expected %s == %s, got: %s", test.name, test.expected.Bits(), test.result.Bits())
+		}
+	}
+}
+
+func TestCompareFloat128(t *testing.T) {
+	one, two := Float128FromFloat64(1), Float128FromFloat64(2)
+	inf, zero, nan := Float128FromFloat64(math.Inf(1)), Float128{}, Float128FromFloat64(math.NaN())
+	tests := []struct {
+		x, y            Float128
+		eq, lt, lte, gt bool
+	}{
+		{one, one, true, false, true, false},
+		{one, two, false, true, true, false},
+		{two, one, false, false, false, true},
+		{one.Neg(), two.Neg(), false, false, false, true},
+		{two.Neg(), one, false, true, true, false},
+		{zero, zero.Neg(), true, false, true, false},
+		{inf.Neg(), inf, false, true, true, false},
+		{nan, nan, false, false, false, false},
+		{nan, one, false, false, false, false},
+		{one, nan, false, false, false, false},
+	}
+	for _, test := range tests {
+		if result := test.x.Eq(test.y); result != test.eq {
+			t.Errorf("Expected %s.Eq(%s) == %v, got: %v", test.x, test.y, test.eq, result)
+		}
+		if result := test.x.Lt(test.y); result != test.lt {
+			t.Errorf("Expected %s.Lt(%s) == %v, got: %v", test.x, test.y, test.lt, result)
+		}
+		if result := test.x.Lte(test.y); result != test.lte {
+			t.Errorf("Expected %s.Lte(%s) == %v, got: %v", test.x, test.y, test.lte, result)
+		}
+		if result := test.x.Gt(test.y); result != test.gt {
+			t.Errorf("Expected %s.Gt(%s) == %v, got: %v", test.x, test.y, test.gt, result)
+		}
+		if result := test.x.Gte(test.y); result != (test.gt || test.eq) {
+			t.Errorf("Expected %s.Gte(%s) == %v, got: %v", test.x, test.y, test.gt || test.eq, result)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package hash128
+
+import (
+	"math/bits"
+
+	"github.com/ryanavella/wide"
+)
+
+const (
+	cityK0 = 0xc3a5c85c97cb3127
+	cityK1 = 0xb492b66fbe98f273
+	cityK2 = 0x9ae16a3b2f90404f
+)
+
+type city struct {
+	buf []byte
+}
+
+// City returns the CityHash128 (v1.1) hash of data
+//
+// The second 64-bit half of the hash is returned in the upper bits of the Uint128.
+func City(data []byte) wide.Uint128 {
+	if len(data) >= 16 {
+		return cityWithSeed(data[16:], le64(data), le64(data[8:])+cityK0)
+	}
+	return cityWithSeed(data, cityK0, cityK1)
+}
+
+// NewCity returns a new CityHash128 (v1.1) hash
+//
+// CityHash is not an incremental hash, so all data written to the returned Hash128 is buffered until Sum or Sum128 is
+// called.
+func NewCity() Hash128 {
+	return new(city)
+}
+
+func (d *city) BlockSize() int {
+	return 64
+}
+
+func (d *city) Reset() {
+	d.buf = d.buf[:0]
+}
+
+func (d *city) Size() int {
+	return size
+}
+
+func (d *city) Sum(b []byte) []byte {
+	return appendUint128(b, d.Sum128())
+}
+
+func (d *city) Sum128() wide.Uint128 {
+	return City(d.buf)
+}
+
+func (d *city) Write(p []byte) (int, error) {
+	d.buf = append(d.buf, p...)
+	return len(p), nil
+}
+
+// cityHash16 hashes 128 bits down to 64 bits
+func cityHash16(u, v uint64) uint64 {
+	return cityHash16Mul(u, v, 0x9ddfea08eb382d69)
+}
+
+// cityHash16Mul hashes 128 bits down to 64 bits, with the given multiplier
+func cityHash16Mul(u, v, mul uint64) uint64 {
+	a := (u ^ v) * mul
+	a ^= a >> 47
+	b := (v ^ a) * mul
+	b ^= b >> 47
+	return b * mul
+}
+
+// cityLen0To16 is the 64-bit CityHash of at most 16 bytes
+func cityLen0To16(p []byte) uint64 {
+	n := uint64(len(p))
+	switch {
+	case n >= 8:
+		mul := cityK2 + n*2
+		a := le64(p) + cityK2
+		b := le64(p[n-8:])
+		c := cityRotate(b, 37)*mul + a
+		d := (cityRotate(a, 25) + b) * mul
+		return cityHash16Mul(c, d, mul)
+	case n >= 4:
+		mul := cityK2 + n*2
+		return cityHash16Mul(n+uint64(le32(p))<<3, uint64(le32(p[n-4:])), mul)
+	case n > 0:
+		y := uint32(p[0]) + uint32(p[n>>1])<<8
+		z := uint32(n) + uint32(p[n-1])<<2
+		return cityShiftMix(uint64(y)*cityK2^uint64(z)*cityK0) * cityK2
+	default:
+		return cityK2
+	}
+}
+
+// cityMurmur is the CityHash128 of fewer than 128 bytes
+func cityMurmur(p []byte, a, b uint64) wide.Uint128 {
+	n := len(p)
+	var c, d uint64
+	if n <= 16 {
+		a = cityShiftMix(a*cityK1) * cityK1
+		c = b*cityK1 + cityLen0To16(p)
+		if n >= 8 {
+			d = cityShiftMix(a + le64(p))
+		} else {
+			d = cityShiftMix(a + c)
+		}
+	} else {
+		c = cityHash16(le64(p[n-8:])+cityK1, a)
+		d = cityHash16(b+uint64(n), c+le64(p[n-16:]))
+		a += d
+		for l := n - 16; ; p = p[16:] {
+			a ^= cityShiftMix(le64(p)*cityK1) * cityK1
+			a *= cityK1
+			b ^= a
+			c ^= cityShiftMix(le64(p[8:])*cityK1) * cityK1
+			c *= cityK1
+			d ^= c
+			if l -= 16; l <= 0 {
+				break
+			}
+		}
+	}
+	a = cityHash16(a, c)
+	b = cityHash16(d, b)
+	return wide.NewUint128(cityHash16(b, a), a^b)
+}
+
+// cityRotate rotates x right by r bits
+func cityRotate(x uint64, r int) uint64 {
+	return bits.RotateLeft64(x, -r)
+}
+
+// cityShiftMix is the shift-xor mix of CityHash
+func cityShiftMix(x uint64) uint64 {
+	return x ^ x>>47
+}
+
+// cityWeak32 returns a 128-bit hash of 32 bytes of input and two seeds, as a pair of 64-bit words
+func cityWeak32(p []byte, a, b uint64) (uint64, uint64) {
+	w, x, y, z := le64(p), le64(p[8:]), le64(p[16:]), le64(p[24:])
+	a += w
+	b = cityRotate(b+a+z, 21)
+	c := a
+	a += x + y
+	b += cityRotate(a, 44)
+	return a + z, b + c
+}
+
+// cityWithSeed is the CityHash128 of p with a 128-bit seed, given as its lower and upper 64-bit words
+func cityWithSeed(p []byte, seedLo, seedHi uint64) wide.Uint128 {
+	if len(p) < 128 {
+		return cityMurmur(p, seedLo, seedHi)
+	}
+	t := p
+	// Keep 56 bytes of state: v, w, x, y and z
+	x, y := seedLo, seedHi
+	z := uint64(len(p)) * cityK1
+	var v0, v1, w0, w1 uint64
+	v0 = cityRotate(y^cityK1, 49)*cityK1 + le64(p)
+	v1 = cityRotate(v0, 42)*cityK1 + le64(p[8:])
+	w0 = cityRotate(y+z, 35)*cityK1 + x
+	w1 = cityRotate(x+le64(p[88:]), 53) * cityK1
+	for len(p) >= 128 {
+		for i := 0; i < 2; i++ {
+			x = cityRotate(x+y+v0+le64(p[8:]), 37) * cityK1
+			y = cityRotate(y+v1+le64(p[48:]), 42) * cityK1
+			x ^= w1
+			y += v0 + le64(p[40:])
+			z = cityRotate(z+w0, 33) * cityK1
+			v0, v1 = cityWeak32(p, v1*cityK1, x+w0)
+			w0, w1 = cityWeak32(p[32:], z+w1, y+le64(p[16:]))
+			z, x = x, z
+			p = p[64:]
+		}
+	}
+	x += cityRotate(v0+z, 49) * cityK0
+	y = y*cityK0 + cityRotate(w1, 37)
+	z = z*cityK0 + cityRotate(w0, 27)
+	w0 *= 9
+	v0 *= cityK0
+	// Hash up to 4 chunks of 32 bytes each from the end of the input
+	for i := 0; i < len(p); {
+		i += 32
+		y = cityRotate(x+y, 42)*cityK0 + v1
+		w0 += le64(t[len(t)-i+16:])
+		x = x*cityK0 + w0
+		z += w1 + le64(t[len(t)-i:])
+		w1 += v0
+		v0, v1 = cityWeak32(t[len(t)-i:], v0+z, v1)
+		v0 *= cityK0
+	}
+	x = cityHash16(x, v0)
+	y = cityHash16(y+z, w0)
+	return wide.NewUint128(cityHash16(x+w1, y+v1), cityHash16(x+v1, w1)+y)
+}
@@ -0,0 +1,53 @@
+package hash128
+
+import (
+	"testing"
+
+	"github.com/ryanavella/wide"
+)
+
+// cityTestData returns the pseudorandom input of the reference CityHash tests
+func cityTestData() []byte {
+	data := make([]byte, 1<<20)
+	a, b := uint64(9), uint64(777)
+	for i := range data {
+		a += b
+		b += a
+		a = (a ^ a>>41) * cityK0
+		b = (b^b>>41)*cityK0 + uint64(i)
+		data[i] = byte(b >> 37)
+	}
+	return data
+}
+
+func TestCity(t *testing.T) {
+	tests := []struct {
+		off, n   int
+		expected wide.Uint128
+	}{
+		{0, 0, wide.NewUint128(0x3cb540c392e51e29, 0x3df09dfc64c09a2b)},
+		{1, 1, wide.NewUint128(0x2c138ff2596d42f6, 0xc3cdc41e1df33513)},
+		{9, 3, wide.NewUint128(0x8b6a8ff06cda8302, 0x2193fb7620cbf23b)},
+		{16, 4, wide.NewUint128(0x666236631b9f253b, 0x4d09e42f09cc3495)},
+		{64, 8, wide.NewUint128(0x55f23b27bb9efd94, 0x26b6689960ccf81d)},
+		{225, 15, wide.NewUint128(0x047e385ff9d4c06f, 0x3bab18b164396783)},
+		{256, 16, wide.NewUint128(0x94d50d3dcd3069a7, 0xac059617f5906673)},
+		{289, 17, wide.NewUint128(0x168fd42f9ecae4ff, 0xa4375590b8ae7c82)},
+		{961, 31, wide.NewUint128(0xa010599d6287c412, 0xb2e25964cd409117)},
+		{1024, 32, wide.NewUint128(0xd848581a580b6c12, 0x9a8c431f500ef06e)},
+		{4096, 64, wide.NewUint128(0xec951ba8e51e3545, 0xd1d44fe99451ef72)},
+		{10000, 100, wide.NewUint128(0xd5983cc93a9d126a, 0x7d3e82d5ba29a90d)},
+		{16129, 127, wide.NewUint128(0xbb57137739ca486b, 0x85b8e53f22e19507)},
+		{16384, 128, wide.NewUint128(0x4aad4e925a962b68, 0xadc52dddb76f6e5e)},
+		{16641, 129, wide.NewUint128(0x86b4a7a0780c2431, 0x0ce030d15b5fe2f4)},
+		{40000, 200, wide.NewUint128(0xfbf55a26790e0ebb, 0x84064a6dcf916340)},
+		{88804, 298, wide.NewUint128(0xd465247cffa415c0, 0x967e970df9673d2a)},
+		{0, 1 << 20, wide.NewUint128(0xd18f23221e964791, 0x6cc09e60700563e9)},
+	}
+	data := cityTestData()
+	for _, test := range tests {
+		if result := City(data[test.off : test.off+test.n]); result != test.expected {
+			t.Errorf("Expected City of %d bytes at offset %d == %s, got: %s", test.n, test.off, test.expected, result)
+		}
+	}
+}
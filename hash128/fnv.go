@@ -0,0 +1,53 @@
+package hash128
+
+import "github.com/ryanavella/wide"
+
+var (
+	fnvOffset = wide.NewUint128(0x6c62272e07bb0142, 0x62b821756295c58d)
+	fnvPrime  = wide.NewUint128(0x0000000001000000, 0x000000000000013b)
+)
+
+type fnv1a struct {
+	h wide.Uint128
+}
+
+// FNV1a returns the 128-bit FNV-1a hash of data
+func FNV1a(data []byte) wide.Uint128 {
+	d := fnv1a{h: fnvOffset}
+	d.Write(data)
+	return d.h
+}
+
+// NewFNV1a returns a new 128-bit FNV-1a hash
+func NewFNV1a() Hash128 {
+	return &fnv1a{h: fnvOffset}
+}
+
+func (d *fnv1a) BlockSize() int {
+	return 1
+}
+
+func (d *fnv1a) Reset() {
+	d.h = fnvOffset
+}
+
+func (d *fnv1a) Size() int {
+	return size
+}
+
+func (d *fnv1a) Sum(b []byte) []byte {
+	return appendUint128(b, d.h)
+}
+
+func (d *fnv1a) Sum128() wide.Uint128 {
+	return d.h
+}
+
+func (d *fnv1a) Write(p []byte) (int, error) {
+	h := d.h
+	for _, c := range p {
+		h = h.Xor(wide.Uint128FromUint64(uint64(c))).Mul(fnvPrime)
+	}
+	d.h = h
+	return len(p), nil
+}
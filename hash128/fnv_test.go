@@ -0,0 +1,37 @@
+package hash128
+
+import (
+	"bytes"
+	"hash/fnv"
+	"math/rand"
+	"testing"
+
+	"github.com/ryanavella/wide"
+)
+
+func TestFNV1a(t *testing.T) {
+	tests := []struct {
+		inp      string
+		expected wide.Uint128
+	}{
+		{"", wide.NewUint128(0x6c62272e07bb0142, 0x62b821756295c58d)},
+		{"a", wide.NewUint128(0xd228cb696f1a8caf, 0x78912b704e4a8964)},
+		{"hello", wide.NewUint128(0xe3e1efd54283d94f, 0x7081314b599d31b3)},
+		{"The quick brown fox jumps over the lazy dog", wide.NewUint128(0x68cce4cd885ea042, 0x39f02af30e297870)},
+	}
+	for _, test := range tests {
+		if result := FNV1a([]byte(test.inp)); result != test.expected {
+			t.Errorf("Expected FNV1a(%q) == %s, got: %s", test.inp, test.expected, result)
+		}
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		data := make([]byte, r.Intn(100))
+		r.Read(data)
+		h := fnv.New128a()
+		h.Write(data)
+		if result, expected := appendUint128(nil, FNV1a(data)), h.Sum(nil); !bytes.Equal(result, expected) {
+			t.Errorf("Expected FNV1a(%x) == %x, got: %x", data, expected, result)
+		}
+	}
+}
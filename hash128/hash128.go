@@ -0,0 +1,37 @@
+// Package hash128 provides non-cryptographic 128-bit hash functions with Uint128 results.
+//
+// Each hash implements hash.Hash, and additionally returns its digest as a Uint128 through Sum128. Sum appends the same
+// digest in big-endian byte order, which is the canonical representation of every hash in this package.
+package hash128
+
+import (
+	"encoding/binary"
+	"hash"
+
+	"github.com/ryanavella/wide"
+)
+
+// Hash128 is the common interface implemented by all 128-bit hash functions
+type Hash128 interface {
+	hash.Hash
+	Sum128() wide.Uint128
+}
+
+// size is the number of bytes appended by Sum, common to all hashes in this package
+const size = 16
+
+// appendUint128 appends a Uint128 to b in big-endian byte order
+func appendUint128(b []byte, x wide.Uint128) []byte {
+	b = binary.BigEndian.AppendUint64(b, x.RShiftN(64).Uint64())
+	return binary.BigEndian.AppendUint64(b, x.Uint64())
+}
+
+// le32 reads a little-endian uint32
+func le32(b []byte) uint32 {
+	return binary.LittleEndian.Uint32(b)
+}
+
+// le64 reads a little-endian uint64
+func le64(b []byte) uint64 {
+	return binary.LittleEndian.Uint64(b)
+}
@@ -0,0 +1,54 @@
+package hash128
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/ryanavella/wide"
+)
+
+// writeChunked writes data to h in chunks of random sizes, including empty chunks, and returns the resulting Sum128
+func writeChunked(h Hash128, data []byte, r *rand.Rand) wide.Uint128 {
+	for len(data) > 0 {
+		n := r.Intn(len(data) + 1)
+		if r.Intn(4) == 0 {
+			n = r.Intn(min(len(data), 2) + 1)
+		}
+		h.Write(data[:n])
+		data = data[n:]
+	}
+	return h.Sum128()
+}
+
+func TestStream(t *testing.T) {
+	hashes := []struct {
+		name string
+		new  func() Hash128
+		sum  func([]byte) wide.Uint128
+	}{
+		{"City", NewCity, City},
+		{"FNV1a", NewFNV1a, FNV1a},
+		{"Murmur3", func() Hash128 { return NewMurmur3(42) }, func(b []byte) wide.Uint128 { return Murmur3(b, 42) }},
+		{"XXH3", NewXXH3, XXH3},
+	}
+	r := rand.New(rand.NewSource(1))
+	for _, hash := range hashes {
+		h := hash.new()
+		if h.Size() != 16 {
+			t.Errorf("Expected %s.Size() == 16, got: %d", hash.name, h.Size())
+		}
+		for i := 0; i < 300; i++ {
+			data := make([]byte, r.Intn(1<<r.Intn(13)))
+			r.Read(data)
+			h.Reset()
+			expected := hash.sum(data)
+			if result := writeChunked(h, data, r); result != expected {
+				t.Errorf("Expected streaming %s of %d bytes == %s, got: %s", hash.name, len(data), expected, result)
+			}
+			if result, expected := h.Sum([]byte{0xff}), appendUint128([]byte{0xff}, expected); !bytes.Equal(result, expected) {
+				t.Errorf("Expected %s.Sum of %d bytes == %x, got: %x", hash.name, len(data), expected, result)
+			}
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package hash128
+
+import (
+	"math/bits"
+
+	"github.com/ryanavella/wide"
+)
+
+const (
+	murmurC1 = 0x87c37b91114253d5
+	murmurC2 = 0x4cf5ad432745937f
+)
+
+type murmur3 struct {
+	h1, h2 uint64
+	tail   [16]byte
+	ntail  int
+	n      uint64
+	seed   uint32
+}
+
+// Murmur3 returns the MurmurHash3 x64_128 hash of data with the given seed
+//
+// The first 64-bit half of the hash (h1) is returned in the upper bits of the Uint128.
+func Murmur3(data []byte, seed uint32) wide.Uint128 {
+	d := murmur3{h1: uint64(seed), h2: uint64(seed), seed: seed}
+	d.Write(data)
+	return d.Sum128()
+}
+
+// NewMurmur3 returns a new MurmurHash3 x64_128 hash with the given seed
+func NewMurmur3(seed uint32) Hash128 {
+	return &murmur3{h1: uint64(seed), h2: uint64(seed), seed: seed}
+}
+
+func (d *murmur3) BlockSize() int {
+	return 16
+}
+
+// block mixes one 16-byte block into the running hash
+func (d *murmur3) block(p []byte) {
+	k1, k2 := le64(p), le64(p[8:])
+	d.h1 ^= bits.RotateLeft64(k1*murmurC1, 31) * murmurC2
+	d.h1 = (bits.RotateLeft64(d.h1, 27)+d.h2)*5 + 0x52dce729
+	d.h2 ^= bits.RotateLeft64(k2*murmurC2, 33) * murmurC1
+	d.h2 = (bits.RotateLeft64(d.h2, 31)+d.h1)*5 + 0x38495ab5
+}
+
+func (d *murmur3) Reset() {
+	*d = murmur3{h1: uint64(d.seed), h2: uint64(d.seed), seed: d.seed}
+}
+
+func (d *murmur3) Size() int {
+	return size
+}
+
+func (d *murmur3) Sum(b []byte) []byte {
+	return appendUint128(b, d.Sum128())
+}
+
+func (d *murmur3) Sum128() wide.Uint128 {
+	h1, h2 := d.h1, d.h2
+	// The unused bytes of the tail are always zero, so it can be read as two whole words
+	k1, k2 := le64(d.tail[:]), le64(d.tail[8:])
+	if d.ntail > 8 {
+		h2 ^= bits.RotateLeft64(k2*murmurC2, 33) * murmurC1
+	}
+	if d.ntail > 0 {
+		h1 ^= bits.RotateLeft64(k1*murmurC1, 31) * murmurC2
+	}
+	h1 ^= d.n
+	h2 ^= d.n
+	h1 += h2
+	h2 += h1
+	h1, h2 = fmix64(h1), fmix64(h2)
+	h1 += h2
+	h2 += h1
+	return wide.NewUint128(h1, h2)
+}
+
+func (d *murmur3) Write(p []byte) (int, error) {
+	n := len(p)
+	d.n += uint64(n)
+	if d.ntail > 0 {
+		c := copy(d.tail[d.ntail:], p)
+		if d.ntail += c; d.ntail < len(d.tail) {
+			return n, nil
+		}
+		d.block(d.tail[:])
+		p = p[c:]
+	}
+	for ; len(p) >= 16; p = p[16:] {
+		d.block(p)
+	}
+	d.tail = [16]byte{}
+	d.ntail = copy(d.tail[:], p)
+	return n, nil
+}
+
+// fmix64 is the 64-bit finalization mix of MurmurHash3
+func fmix64(k uint64) uint64 {
+	k ^= k >> 33
+	k *= 0xff51afd7ed558ccd
+	k ^= k >> 33
+	k *= 0xc4ceb9fe1a85ec53
+	k ^= k >> 33
+	return k
+}
@@ -0,0 +1,29 @@
+package hash128
+
+import (
+	"testing"
+
+	"github.com/ryanavella/wide"
+)
+
+func TestMurmur3(t *testing.T) {
+	tests := []struct {
+		inp      string
+		seed     uint32
+		expected wide.Uint128
+	}{
+		{"", 0, wide.NewUint128(0, 0)},
+		{"", 42, wide.NewUint128(0xf02aa77dfa1b8523, 0xd1016610da11cbb9)},
+		{"a", 0, wide.NewUint128(0x85555565f6597889, 0xe6b53a48510e895a)},
+		{"a", 42, wide.NewUint128(0x28259ca4fdf626b0, 0x25ebca9125f82b15)},
+		{"hello", 0, wide.NewUint128(0xcbd8a7b341bd9b02, 0x5b1e906a48ae1d19)},
+		{"hello", 42, wide.NewUint128(0xc4b8b3c960af6f08, 0x2334b875b0efbc7a)},
+		{"The quick brown fox jumps over the lazy dog", 0, wide.NewUint128(0xe34bbc7bbc071b6c, 0x7a433ca9c49a9347)},
+		{"The quick brown fox jumps over the lazy dog", 42, wide.NewUint128(0x740dcf93fe0bd5d7, 0xc4546cf4ec705c8f)},
+	}
+	for _, test := range tests {
+		if result := Murmur3([]byte(test.inp), test.seed); result != test.expected {
+			t.Errorf("Expected Murmur3(%q, %d) == %s, got: %s", test.inp, test.seed, test.expected, result)
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package hash128
+
+import (
+	"math/bits"
+
+	"github.com/ryanavella/wide"
+)
+
+const (
+	xxhPrime32_1 = 0x9e3779b1
+	xxhPrime32_2 = 0x85ebca77
+	xxhPrime32_3 = 0xc2b2ae3d
+	xxhPrime64_1 = 0x9e3779b185ebca87
+	xxhPrime64_2 = 0xc2b2ae3d27d4eb4f
+	xxhPrime64_3 = 0x165667b19e3779f9
+	xxhPrime64_4 = 0x85ebca77c2b2ae63
+	xxhPrime64_5 = 0x27d4eb2f165667c5
+	xxhPrimeMx2  = 0x9fb21c651e98df25
+
+	xxhStripeLen = 64
+	xxhBlockLen  = 1024 // (len(xxhSecret) - xxhStripeLen) / 8 stripes
+)
+
+// xxhSecret is the default secret of XXH3
+var xxhSecret = [192]byte{
+	0xb8, 0xfe, 0x6c, 0x39, 0x23, 0xa4, 0x4b, 0xbe, 0x7c, 0x01, 0x81, 0x2c, 0xf7, 0x21, 0xad, 0x1c,
+	0xde, 0xd4, 0x6d, 0xe9, 0x83, 0x90, 0x97, 0xdb, 0x72, 0x40, 0xa4, 0xa4, 0xb7, 0xb3, 0x67, 0x1f,
+	0xcb, 0x79, 0xe6, 0x4e, 0xcc, 0xc0, 0xe5, 0x78, 0x82, 0x5a, 0xd0, 0x7d, 0xcc, 0xff, 0x72, 0x21,
+	0xb8, 0x08, 0x46, 0x74, 0xf7, 0x43, 0x24, 0x8e, 0xe0, 0x35, 0x90, 0xe6, 0x81, 0x3a, 0x26, 0x4c,
+	0x3c, 0x28, 0x52, 0xbb, 0x91, 0xc3, 0x00, 0xcb, 0x88, 0xd0, 0x65, 0x8b, 0x1b, 0x53, 0x2e, 0xa3,
+	0x71, 0x64, 0x48, 0x97, 0xa2, 0x0d, 0xf9, 0x4e, 0x38, 0x19, 0xef, 0x46, 0xa9, 0xde, 0xac, 0xd8,
+	0xa8, 0xfa, 0x76, 0x3f, 0xe3, 0x9c, 0x34, 0x3f, 0xf9, 0xdc, 0xbb, 0xc7, 0xc7, 0x0b, 0x4f, 0x1d,
+	0x8a, 0x51, 0xe0, 0x4b, 0xcd, 0xb4, 0x59, 0x31, 0xc8, 0x9f, 0x7e, 0xc9, 0xd9, 0x78, 0x73, 0x64,
+	0xea, 0xc5, 0xac, 0x83, 0x34, 0xd3, 0xeb, 0xc3, 0xc5, 0x81, 0xa0, 0xff, 0xfa, 0x13, 0x63, 0xeb,
+	0x17, 0x0d, 0xdd, 0x51, 0xb7, 0xf0, 0xda, 0x49, 0xd3, 0x16, 0x55, 0x26, 0x29, 0xd4, 0x68, 0x9e,
+	0x2b, 0x16, 0xbe, 0x58, 0x7d, 0x47, 0xa1, 0xfc, 0x8f, 0xf8, 0xb8, 0xd1, 0x7a, 0xd0, 0x31, 0xce,
+	0x45, 0xcb, 0x3a, 0x8f, 0x95, 0x16, 0x04, 0x28, 0xaf, 0xd7, 0xfb, 0xca, 0xbb, 0x4b, 0x40, 0x7e,
+}
+
+// xxhInitAcc is the initial state of the accumulators, for inputs longer than 240 bytes
+var xxhInitAcc = [8]uint64{
+	xxhPrime32_3, xxhPrime64_1, xxhPrime64_2, xxhPrime64_3,
+	xxhPrime64_4, xxhPrime32_2, xxhPrime64_5, xxhPrime32_1,
+}
+
+type xxh3 struct {
+	acc  [8]uint64
+	buf  [xxhBlockLen + xxhStripeLen]byte
+	nbuf int
+	nblk uint64
+}
+
+// NewXXH3 returns a new XXH3-128 hash, with the default secret and no seed
+func NewXXH3() Hash128 {
+	return &xxh3{acc: xxhInitAcc}
+}
+
+// XXH3 returns the XXH3-128 hash of data, with the default secret and no seed
+func XXH3(data []byte) wide.Uint128 {
+	switch n := len(data); {
+	case n <= 16:
+		return xxh3Len0To16(data)
+	case n <= 128:
+		return xxh3Len17To128(data)
+	case n <= 240:
+		return xxh3Len129To240(data)
+	}
+	acc := xxhInitAcc
+	xxh3Accumulate(&acc, data)
+	return xxh3Merge(&acc, uint64(len(data)))
+}
+
+func (d *xxh3) BlockSize() int {
+	return xxhStripeLen
+}
+
+func (d *xxh3) Reset() {
+	d.acc, d.nbuf, d.nblk = xxhInitAcc, 0, 0
+}
+
+func (d *xxh3) Size() int {
+	return size
+}
+
+func (d *xxh3) Sum(b []byte) []byte {
+	return appendUint128(b, d.Sum128())
+}
+
+func (d *xxh3) Sum128() wide.Uint128 {
+	if d.nblk == 0 {
+		return XXH3(d.buf[:d.nbuf])
+	}
+	// Once a block has been consumed, at least one stripe always remains buffered, so the last stripe is never lost
+	acc := d.acc
+	xxh3Accumulate(&acc, d.buf[:d.nbuf])
+	return xxh3Merge(&acc, d.nblk*xxhBlockLen+uint64(d.nbuf))
+}
+
+func (d *xxh3) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		if d.nbuf == len(d.buf) {
+			// Only consume a block when more input follows it, since the last stripe is treated specially
+			xxh3Block(&d.acc, d.buf[:xxhBlockLen])
+			d.nbuf = copy(d.buf[:], d.buf[xxhBlockLen:])
+			d.nblk++
+		}
+		c := copy(d.buf[d.nbuf:], p)
+		d.nbuf += c
+		p = p[c:]
+	}
+	return n, nil
+}
+
+// xxh3Accumulate consumes all remaining input for a hash longer than 240 bytes, where p holds at least one stripe
+func xxh3Accumulate(acc *[8]uint64, p []byte) {
+	// The last stripe is always the last 64 bytes of input, which may overlap with the last block
+	last := p[len(p)-xxhStripeLen:]
+	for ; len(p) > xxhBlockLen; p = p[xxhBlockLen:] {
+		xxh3Block(acc, p)
+	}
+	n := (len(p) - 1) / xxhStripeLen
+	for i := 0; i < n; i++ {
+		xxh3Stripe(acc, p[i*xxhStripeLen:], xxhSecret[i*8:])
+	}
+	xxh3Stripe(acc, last, xxhSecret[len(xxhSecret)-xxhStripeLen-7:])
+}
+
+// xxh3Avalanche is the final mix of XXH3
+func xxh3Avalanche(h uint64) uint64 {
+	h ^= h >> 37
+	h *= 0x165667919e3779f9
+	return h ^ h>>32
+}
+
+// xxh3Block consumes one full block of input, and then scrambles the accumulators
+func xxh3Block(acc *[8]uint64, p []byte) {
+	for i := 0; i < xxhBlockLen/xxhStripeLen; i++ {
+		xxh3Stripe(acc, p[i*xxhStripeLen:], xxhSecret[i*8:])
+	}
+	s := xxhSecret[len(xxhSecret)-xxhStripeLen:]
+	for i := range acc {
+		a := acc[i]
+		a ^= a >> 47
+		a ^= le64(s[8*i:])
+		acc[i] = a * xxhPrime32_1
+	}
+}
+
+// xxh3Len0To16 returns the XXH3-128 hash of at most 16 bytes
+func xxh3Len0To16(p []byte) wide.Uint128 {
+	n := uint64(len(p))
+	switch {
+	case n > 8:
+		lo, hi := le64(p), le64(p[n-8:])
+		mhi, mlo := bits.Mul64(lo^hi^le64(xxhSecret[32:])^le64(xxhSecret[40:]), xxhPrime64_1)
+		mlo += (n - 1) << 54
+		hi ^= le64(xxhSecret[48:]) ^ le64(xxhSecret[56:])
+		mhi += hi + uint64(uint32(hi))*(xxhPrime32_2-1)
+		mlo ^= bits.ReverseBytes64(mhi)
+		hhi, hlo := bits.Mul64(mlo, xxhPrime64_2)
+		hhi += mhi * xxhPrime64_2
+		return wide.NewUint128(xxh3Avalanche(hhi), xxh3Avalanche(hlo))
+	case n >= 4:
+		in := uint64(le32(p)) + uint64(le32(p[n-4:]))<<32
+		mhi, mlo := bits.Mul64(in^le64(xxhSecret[16:])^le64(xxhSecret[24:]), xxhPrime64_1+n<<2)
+		mhi += mlo << 1
+		mlo ^= mhi >> 3
+		mlo ^= mlo >> 35
+		mlo *= xxhPrimeMx2
+		mlo ^= mlo >> 28
+		return wide.NewUint128(xxh3Avalanche(mhi), mlo)
+	case n > 0:
+		c1, c2, c3 := uint32(p[0]), uint32(p[n>>1]), uint32(p[n-1])
+		lo := c1<<16 | c2<<24 | c3 | uint32(n)<<8
+		hi := bits.RotateLeft32(bits.ReverseBytes32(lo), 13)
+		return wide.NewUint128(
+			xxh64Avalanche(uint64(hi^le32(xxhSecret[8:])^le32(xxhSecret[12:]))),
+			xxh64Avalanche(uint64(lo^le32(xxhSecret[0:])^le32(xxhSecret[4:]))),
+		)
+	default:
+		return wide.NewUint128(
+			xxh64Avalanche(le64(xxhSecret[80:])^le64(xxhSecret[88:])),
+			xxh64Avalanche(le64(xxhSecret[64:])^le64(xxhSecret[72:])),
+		)
+	}
+}
+
+// xxh3Len17To128 returns the XXH3-128 hash of 17 to 128 bytes
+func xxh3Len17To128(p []byte) wide.Uint128 {
+	n := len(p)
+	lo, hi := uint64(n)*xxhPrime64_1, uint64(0)
+	if n > 32 {
+		if n > 64 {
+			if n > 96 {
+				lo, hi = xxh3Mix32(lo, hi, p[48:], p[n-64:], xxhSecret[96:])
+			}
+			lo, hi = xxh3Mix32(lo, hi, p[32:], p[n-48:], xxhSecret[64:])
+		}
+		lo, hi = xxh3Mix32(lo, hi, p[16:], p[n-32:], xxhSecret[32:])
+	}
+	lo, hi = xxh3Mix32(lo, hi, p, p[n-16:], xxhSecret[:])
+	return xxh3Finish(lo, hi, uint64(n))
+}
+
+// xxh3Len129To240 returns the XXH3-128 hash of 129 to 240 bytes
+func xxh3Len129To240(p []byte) wide.Uint128 {
+	n := len(p)
+	lo, hi := uint64(n)*xxhPrime64_1, uint64(0)
+	for i := 0; i < 4; i++ {
+		lo, hi = xxh3Mix32(lo, hi, p[32*i:], p[32*i+16:], xxhSecret[32*i:])
+	}
+	lo, hi = xxh3Avalanche(lo), xxh3Avalanche(hi)
+	for i := 4; i < n/32; i++ {
+		lo, hi = xxh3Mix32(lo, hi, p[32*i:], p[32*i+16:], xxhSecret[32*(i-4)+3:])
+	}
+	lo, hi = xxh3Mix32(lo, hi, p[n-16:], p[n-32:], xxhSecret[136-17-16:])
+	return xxh3Finish(lo, hi, uint64(n))
+}
+
+// xxh3Finish returns the XXH3-128 hash of 17 to 240 bytes, from its final pair of accumulators
+func xxh3Finish(lo, hi, n uint64) wide.Uint128 {
+	return wide.NewUint128(
+		-xxh3Avalanche(lo*xxhPrime64_1+hi*xxhPrime64_4+n*xxhPrime64_2),
+		xxh3Avalanche(lo+hi),
+	)
+}
+
+// xxh3Merge returns the XXH3-128 hash of n bytes, from the accumulators of a hash longer than 240 bytes
+func xxh3Merge(acc *[8]uint64, n uint64) wide.Uint128 {
+	merge := func(s []byte, h uint64) uint64 {
+		for i := 0; i < 4; i++ {
+			h += xxh3MulFold(acc[2*i]^le64(s[16*i:]), acc[2*i+1]^le64(s[16*i+8:]))
+		}
+		return xxh3Avalanche(h)
+	}
+	return wide.NewUint128(
+		merge(xxhSecret[len(xxhSecret)-xxhStripeLen-11:], ^(n*xxhPrime64_2)),
+		merge(xxhSecret[11:], n*xxhPrime64_1),
+	)
+}
+
+// xxh3Mix32 mixes two 16-byte inputs into a pair of accumulators
+func xxh3Mix32(lo, hi uint64, p, q, s []byte) (uint64, uint64) {
+	lo += xxh3MulFold(le64(p)^le64(s), le64(p[8:])^le64(s[8:]))
+	lo ^= le64(q) + le64(q[8:])
+	hi += xxh3MulFold(le64(q)^le64(s[16:]), le64(q[8:])^le64(s[24:]))
+	hi ^= le64(p) + le64(p[8:])
+	return lo, hi
+}
+
+// xxh3MulFold returns the xor of the upper and lower halves of the 128-bit product of x and y
+func xxh3MulFold(x, y uint64) uint64 {
+	hi, lo := bits.Mul64(x, y)
+	return hi ^ lo
+}
+
+// xxh3Stripe consumes one stripe of input into the accumulators
+func xxh3Stripe(acc *[8]uint64, p, s []byte) {
+	for i := range acc {
+		v := le64(p[8*i:])
+		k := v ^ le64(s[8*i:])
+		acc[i^1] += v
+		acc[i] += uint64(uint32(k)) * (k >> 32)
+	}
+}
+
+// xxh64Avalanche is the final mix of XXH64
+func xxh64Avalanche(h uint64) uint64 {
+	h ^= h >> 33
+	h *= xxhPrime64_2
+	h ^= h >> 29
+	h *= xxhPrime64_3
+	return h ^ h>>32
+}
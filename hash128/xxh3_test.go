@@ -0,0 +1,52 @@
+package hash128
+
+import (
+	"testing"
+
+	"github.com/ryanavella/wide"
+)
+
+func TestXXH3(t *testing.T) {
+	// Reference vectors for the input bytes 1, 2, ..., 250, 0, 1, ... truncated to n bytes
+	tests := []struct {
+		n        int
+		expected wide.Uint128
+	}{
+		{0, wide.NewUint128(0x99aa06d3014798d8, 0x6001c324468d497f)},
+		{1, wide.NewUint128(0x51025a4491835505, 0xe12ef9d2eb86ceeb)},
+		{2, wide.NewUint128(0xd2f0f9428898845f, 0x08130b77ddef5807)},
+		{3, wide.NewUint128(0xac77eb88cbc4b8d4, 0xebce9b7632ae733b)},
+		{4, wide.NewUint128(0x49a04899597a3567, 0x537653a0d9955b86)},
+		{8, wide.NewUint128(0x2ab463fddb09a0b8, 0x3e8675c57268fb02)},
+		{9, wide.NewUint128(0xe338e616502be361, 0x3c4087b7dea54fc0)},
+		{16, wide.NewUint128(0x6d84a882f6411b41, 0xeada823104bd7174)},
+		{17, wide.NewUint128(0x9ac14e2c3fe59a83, 0xacda8373034d6aaf)},
+		{32, wide.NewUint128(0x6558716845a29a6c, 0xd4e2dfc12b4b57ed)},
+		{33, wide.NewUint128(0x511310aba0443aac, 0xe0e91dd44e49b7d0)},
+		{64, wide.NewUint128(0x21bfcfd7d148a3df, 0x639fbd9cf9bdfb51)},
+		{65, wide.NewUint128(0x2f2ef5c0f071aabf, 0x839a6f242d0884a1)},
+		{96, wide.NewUint128(0x2c932ae1bf5ef99d, 0xdf5af453c37a4873)},
+		{97, wide.NewUint128(0x3848b83ec10ae2b5, 0xfbe19870ccd77f9f)},
+		{128, wide.NewUint128(0x763fdbd9fc602233, 0x7ac9e58028da0fc7)},
+		{129, wide.NewUint128(0x4c8ce7bd2a6024b3, 0x88cb4305c9a32490)},
+		{200, wide.NewUint128(0x4d5d2c739a03aef0, 0xaf7e5fb544c9ec7a)},
+		{240, wide.NewUint128(0x2007e6f83d506ea3, 0xdde80e1ba2971e09)},
+		{241, wide.NewUint128(0x956bc01534a3752b, 0xb6515f490cdd4ce5)},
+		{1024, wide.NewUint128(0x5810ff822ad52808, 0x546f61a5b0b850c1)},
+		{1025, wide.NewUint128(0xe1f3816b3e91c48a, 0xa58696e72de6df58)},
+		{1088, wide.NewUint128(0xda6203c4721e38fa, 0x3e19d2125c286a5a)},
+		{1089, wide.NewUint128(0x3c2d882be82e3ee0, 0xe6b8f8f8df938429)},
+		{2048, wide.NewUint128(0x566400e724b4cb56, 0x97ca16b9cb0322d1)},
+		{2367, wide.NewUint128(0x4b23d4866fa82d26, 0xdbcd9b054f90d871)},
+		{4095, wide.NewUint128(0x8b81e7baaa6cdbac, 0x268198759d7bdf74)},
+	}
+	data := make([]byte, 4096)
+	for i := range data {
+		data[i] = byte((i + 1) % 251)
+	}
+	for _, test := range tests {
+		if result := XXH3(data[:test.n]); result != test.expected {
+			t.Errorf("Expected XXH3 of %d bytes == %s, got: %s", test.n, test.expected, result)
+		}
+	}
+}
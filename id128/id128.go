@@ -0,0 +1,108 @@
+// Package id128 provides generators of time-ordered 128-bit identifiers, as Uint128's.
+//
+// Every generator is safe for concurrent use, and takes an injectable clock so that its output can be reproduced in tests.
+// The identifiers generated by a single generator are strictly increasing, even if the clock stands still or goes backwards. A
+// Snowflake, whose timestamp field may be narrow, returns an error rather than an identifier which would not be increasing.
+package id128
+
+import (
+	"errors"
+	mrand "math/rand/v2"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ryanavella/wide"
+	"github.com/ryanavella/wide/internal/randsrc"
+)
+
+// crockford is the Crockford base32 alphabet, which excludes I, L, O and U
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// base32Len is the number of Crockford base32 digits in a Uint128
+const base32Len = 26
+
+// monotonic is the common state of the generators which follow a timestamp with random bits
+type monotonic struct {
+	mu   sync.Mutex
+	now  func() time.Time
+	src  mrand.Source
+	last wide.Uint128
+}
+
+// next returns a timestamp followed by randBits random bits, or increments the last identifier if the timestamp has not advanced
+//
+// An increment which overflows the random bits carries into the timestamp, so that the identifiers stay strictly increasing.
+func (m *monotonic) next(stamp func(time.Time) uint64, randBits uint) wide.Uint128 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.now == nil {
+		m.now = time.Now
+	}
+	if m.src == nil {
+		m.src = randsrc.Crypto{}
+	}
+	t := stamp(m.now())
+	if m.last != (wide.Uint128{}) && t <= m.last.RShiftN(randBits).Uint64() {
+		m.last = m.last.Inc()
+		return m.last
+	}
+	r := wide.NewUint128(m.src.Uint64(), m.src.Uint64()).RShiftN(128 - randBits)
+	m.last = wide.Uint128FromUint64(t).LShiftN(randBits).Or(r)
+	return m.last
+}
+
+// DecodeBase32 parses a Uint128 from its 26-digit Crockford base32 representation, as used by ULIDs
+//
+// Decoding is case-insensitive, and the letters I and L are read as 1 and the letter O as 0. The first digit must be at most 7,
+// since 26 digits hold 130 bits.
+func DecodeBase32(s string) (wide.Uint128, error) {
+	if len(s) != base32Len {
+		return wide.Uint128{}, errors.New("id128: invalid base32 " + strconv.Quote(s))
+	}
+	var z wide.Uint128
+	for i := 0; i < len(s); i++ {
+		d := base32Digit(s[i])
+		if d < 0 || i == 0 && d > 7 {
+			return wide.Uint128{}, errors.New("id128: invalid base32 " + strconv.Quote(s))
+		}
+		z = z.LShiftN(5).Or(wide.Uint128FromUint64(uint64(d)))
+	}
+	return z, nil
+}
+
+// EncodeBase32 returns the 26-digit uppercase Crockford base32 representation of a Uint128, as used by ULIDs
+func EncodeBase32(x wide.Uint128) string {
+	var buf [base32Len]byte
+	for i := len(buf) - 1; i >= 0; i-- {
+		buf[i] = crockford[x.Uint64()&31]
+		x = x.RShiftN(5)
+	}
+	return string(buf[:])
+}
+
+// base32Digit returns the value of a Crockford base32 digit, or -1 if it is invalid
+func base32Digit(c byte) int {
+	switch {
+	case '0' <= c && c <= '9':
+		return int(c - '0')
+	case 'a' <= c && c <= 'z':
+		c -= 'a' - 'A'
+	case c < 'A' || 'Z' < c:
+		return -1
+	}
+	switch c {
+	case 'I', 'L':
+		return 1
+	case 'O':
+		return 0
+	case 'U':
+		return -1
+	}
+	for i := 10; i < len(crockford); i++ {
+		if crockford[i] == c {
+			return i
+		}
+	}
+	return -1
+}
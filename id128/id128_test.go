@@ -0,0 +1,45 @@
+package id128
+
+import (
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	"github.com/ryanavella/wide"
+)
+
+func TestBase32(t *testing.T) {
+	tests := []struct {
+		x        wide.Uint128
+		expected string
+	}{
+		{wide.Uint128{}, "00000000000000000000000000"},
+		{wide.Uint128FromUint64(31), "0000000000000000000000000Z"},
+		{wide.NewUint128(1<<64-1, 1<<64-1), "7ZZZZZZZZZZZZZZZZZZZZZZZZZ"},
+		{wide.NewUint128(0x01563e3ab5d30000, 0), "01ARZ3NDEK0000000000000000"},
+	}
+	for _, test := range tests {
+		if result := EncodeBase32(test.x); result != test.expected {
+			t.Errorf("Expected EncodeBase32(%s) == %s, got: %s", test.x, test.expected, result)
+		}
+		if result, err := DecodeBase32(strings.ToLower(test.expected)); err != nil || result != test.x {
+			t.Errorf("Expected DecodeBase32(%q) == %s, got: %s, %v", strings.ToLower(test.expected), test.x, result, err)
+		}
+	}
+	if result, err := DecodeBase32("0000000000000000000000000L"); err != nil || result != wide.Uint128FromUint64(1) {
+		t.Errorf("Expected DecodeBase32(%q) == 0x1, got: %s, %v", "0000000000000000000000000L", result, err)
+	}
+	for _, inp := range []string{"", "0000000000000000000000000", "000000000000000000000000000", "80000000000000000000000000",
+		"0000000000000000000000000U", "0000000000000000000000000-"} {
+		if result, err := DecodeBase32(inp); err == nil {
+			t.Errorf("Expected DecodeBase32(%q) to fail, got: %s", inp, result)
+		}
+	}
+	r := rand.New(rand.NewPCG(1, 2))
+	for i := 0; i < 1000; i++ {
+		x := wide.NewUint128(r.Uint64(), r.Uint64())
+		if result, err := DecodeBase32(EncodeBase32(x)); err != nil || result != x {
+			t.Fatalf("Expected DecodeBase32(EncodeBase32(%s)) == %s, got: %s, %v", x, x, result, err)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package id128
+
+import (
+	mrand "math/rand/v2"
+	"time"
+
+	"github.com/ryanavella/wide"
+)
+
+const (
+	// ksuidEpoch is the Unix time of the KSUID epoch, 2014-05-13 16:53:20 UTC
+	ksuidEpoch = 1400000000
+	// ksuidRandBits is the number of random bits which follow the 32-bit timestamp of a KSUID-style identifier
+	ksuidRandBits = 96
+)
+
+// KSUIDGenerator generates KSUID-style identifiers, which are a 32-bit timestamp in seconds since the KSUID epoch followed
+// by 96 random bits
+//
+// Unlike a KSUID, whose payload is 128 bits, the identifiers fit in a Uint128. Within the same second, each identifier is the
+// previous one incremented by 1. The zero value uses time.Now and crypto/rand.
+type KSUIDGenerator struct {
+	m monotonic
+}
+
+// NewKSUIDGenerator returns a KSUIDGenerator with the given clock and random source
+//
+// A nil clock defaults to time.Now, and a nil source defaults to crypto/rand.
+func NewKSUIDGenerator(now func() time.Time, src mrand.Source) *KSUIDGenerator {
+	return &KSUIDGenerator{m: monotonic{now: now, src: src}}
+}
+
+// KSUIDTime returns the timestamp of a KSUID-style identifier
+func KSUIDTime(x wide.Uint128) time.Time {
+	return time.Unix(int64(x.RShiftN(ksuidRandBits).Uint64())+ksuidEpoch, 0)
+}
+
+// Next returns a new KSUID-style identifier
+//
+// Times before the KSUID epoch, or after 2150, wrap modulo 2^32 seconds.
+func (g *KSUIDGenerator) Next() wide.Uint128 {
+	return g.m.next(func(t time.Time) uint64 {
+		return uint64(uint32(t.Unix() - ksuidEpoch))
+	}, ksuidRandBits)
+}
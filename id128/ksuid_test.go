@@ -0,0 +1,27 @@
+package id128
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ryanavella/wide"
+)
+
+func TestKSUIDGenerator(t *testing.T) {
+	now := time.Unix(ksuidEpoch+0x0c0ffee0, 0)
+	g := NewKSUIDGenerator(func() time.Time { return now }, wide.NewPCG64(wide.Uint128FromUint64(1), wide.Uint128{}))
+	x := g.Next()
+	if ts := x.RShiftN(96).Uint64(); ts != 0x0c0ffee0 {
+		t.Errorf("Expected KSUID-style identifier with timestamp 0xc0ffee0, got: %#x", ts)
+	}
+	if result := KSUIDTime(x); !result.Equal(now) {
+		t.Errorf("Expected KSUIDTime(%s) == %s, got: %s", x, now, result)
+	}
+	if y := g.Next(); y != x.Inc() {
+		t.Errorf("Expected KSUID-style identifier %s to follow %s, got: %s", x.Inc(), x, y)
+	}
+	now = now.Add(time.Second)
+	if y := g.Next(); y.RShiftN(96).Uint64() != 0x0c0ffee1 {
+		t.Errorf("Expected KSUID-style identifier with timestamp 0xc0ffee1, got: %s", y)
+	}
+}
@@ -0,0 +1,100 @@
+package id128
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ryanavella/wide"
+)
+
+// SnowflakeLayout is the layout of a Snowflake-128 identifier, which is a timestamp, a node ID and a sequence number, from the
+// most significant bits to the least
+//
+// Each field is at most 64 bits wide, and the fields together are at most 128 bits wide. The timestamp counts units since the
+// epoch, and a zero unit defaults to a millisecond.
+type SnowflakeLayout struct {
+	TimeBits, NodeBits, SeqBits uint
+	Epoch                       time.Time
+	Unit                        time.Duration
+}
+
+// Snowflake generates Snowflake-128 identifiers for a single node
+//
+// A Snowflake is safe for concurrent use. When the sequence number overflows within a unit of time, or the clock goes
+// backwards, the timestamp is advanced past the clock instead of waiting for it. Once the timestamp overflows its field, Next
+// returns an error instead of wrapping around.
+type Snowflake struct {
+	mu       sync.Mutex
+	now      func() time.Time
+	layout   SnowflakeLayout
+	node     uint64
+	started  bool
+	lastTime uint64
+	seq      uint64
+}
+
+// NewSnowflake returns a Snowflake with the given layout, node ID and clock
+//
+// A nil clock defaults to time.Now. An error is returned if the layout is invalid, or if the node ID does not fit in it.
+func NewSnowflake(layout SnowflakeLayout, node uint64, now func() time.Time) (*Snowflake, error) {
+	if layout.TimeBits > 64 || layout.NodeBits > 64 || layout.SeqBits > 64 || layout.TimeBits+layout.NodeBits+layout.SeqBits > 128 {
+		return nil, errors.New("id128: invalid Snowflake layout")
+	}
+	if node > mask(layout.NodeBits) {
+		return nil, errors.New("id128: Snowflake node ID out of range")
+	}
+	if layout.Unit <= 0 {
+		layout.Unit = time.Millisecond
+	}
+	if now == nil {
+		now = time.Now
+	}
+	return &Snowflake{now: now, layout: layout, node: node}, nil
+}
+
+// mask returns a mask of the lower bits bits of a uint64
+func mask(bits uint) uint64 {
+	return 1<<bits - 1
+}
+
+// Next returns a new Snowflake-128 identifier
+//
+// Times before the epoch are treated as the epoch. An error is returned once the timestamp no longer fits in its field, since
+// the identifiers would otherwise wrap around and stop increasing.
+func (s *Snowflake) Next() (wide.Uint128, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	l := s.layout
+	var t uint64
+	if d := s.now().Sub(l.Epoch); d > 0 {
+		t = uint64(d / l.Unit)
+	}
+	seq := uint64(0)
+	switch {
+	case !s.started || t > s.lastTime:
+	case s.seq < mask(l.SeqBits):
+		t, seq = s.lastTime, s.seq+1
+	case s.lastTime < mask(l.TimeBits):
+		t = s.lastTime + 1
+	default:
+		return wide.Uint128{}, errors.New("id128: Snowflake timestamp overflow")
+	}
+	if t > mask(l.TimeBits) {
+		return wide.Uint128{}, errors.New("id128: Snowflake timestamp overflow")
+	}
+	s.started, s.lastTime, s.seq = true, t, seq
+	z := wide.Uint128FromUint64(t).LShiftN(l.NodeBits + l.SeqBits)
+	return z.Or(wide.Uint128FromUint64(s.node).LShiftN(l.SeqBits)).Or(wide.Uint128FromUint64(seq)), nil
+}
+
+// Split returns the time, node ID and sequence number of a Snowflake-128 identifier
+//
+// The time is truncated to the unit of the layout.
+func (s *Snowflake) Split(x wide.Uint128) (t time.Time, node, seq uint64) {
+	l := s.layout
+	seq = x.Uint64() & mask(l.SeqBits)
+	node = x.RShiftN(l.SeqBits).Uint64() & mask(l.NodeBits)
+	ts := x.RShiftN(l.NodeBits+l.SeqBits).Uint64() & mask(l.TimeBits)
+	return l.Epoch.Add(time.Duration(ts) * l.Unit), node, seq
+}
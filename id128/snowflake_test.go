@@ -0,0 +1,100 @@
+package id128
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ryanavella/wide"
+)
+
+func TestSnowflake(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := epoch.Add(1234 * time.Millisecond)
+	layout := SnowflakeLayout{TimeBits: 64, NodeBits: 48, SeqBits: 2, Epoch: epoch}
+	s, err := NewSnowflake(layout, 0xabcdef, func() time.Time { return now })
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []wide.Uint128{
+		wide.NewUint128(1234>>14, 1234<<50|0xabcdef<<2|0),
+		wide.NewUint128(1234>>14, 1234<<50|0xabcdef<<2|1),
+		wide.NewUint128(1234>>14, 1234<<50|0xabcdef<<2|2),
+		wide.NewUint128(1234>>14, 1234<<50|0xabcdef<<2|3),
+		// The sequence number overflows, and the timestamp is advanced past the clock
+		wide.NewUint128(1235>>14, 1235<<50|0xabcdef<<2|0),
+		wide.NewUint128(1235>>14, 1235<<50|0xabcdef<<2|1),
+	}
+	for i, e := range expected {
+		if result, err := s.Next(); err != nil || result != e {
+			t.Errorf("Expected Snowflake %d == %s, got: %s, %v", i, e, result, err)
+		}
+	}
+	// The clock goes backwards
+	now = now.Add(-time.Second)
+	if result, err := s.Next(); err != nil || result != wide.NewUint128(1235>>14, 1235<<50|0xabcdef<<2|2) {
+		t.Errorf("Expected Snowflake == %s, got: %s, %v", wide.NewUint128(1235>>14, 1235<<50|0xabcdef<<2|2), result, err)
+	}
+	now = now.Add(2 * time.Second)
+	x, err := s.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ts, node, seq := s.Split(x); !ts.Equal(now) || node != 0xabcdef || seq != 0 {
+		t.Errorf("Expected Split(%s) == %s, 0xabcdef, 0, got: %s, %#x, %d", x, now, ts, node, seq)
+	}
+}
+
+func TestSnowflakeOverflow(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := epoch.Add(6 * time.Second)
+	layout := SnowflakeLayout{TimeBits: 3, NodeBits: 4, SeqBits: 1, Epoch: epoch, Unit: time.Second}
+	s, err := NewSnowflake(layout, 5, func() time.Time { return now })
+	if err != nil {
+		t.Fatal(err)
+	}
+	var last wide.Uint128
+	for i := 0; i < 4; i++ {
+		// The last two identifiers advance the timestamp past the clock, into the last value which fits in 3 bits
+		x, err := s.Next()
+		if err != nil {
+			t.Fatalf("Expected Snowflake %d to succeed, got: %v", i, err)
+		}
+		if i > 0 && !x.Gt(last) {
+			t.Errorf("Expected Snowflake %d == %s to be greater than %s", i, x, last)
+		}
+		last = x
+	}
+	if x, err := s.Next(); err == nil {
+		t.Errorf("Expected Snowflake to overflow, got: %s", x)
+	}
+	// The clock runs past the timestamp field
+	now = epoch.Add(100 * time.Second)
+	if x, err := s.Next(); err == nil {
+		t.Errorf("Expected Snowflake to overflow, got: %s", x)
+	}
+	// A full-width timestamp cannot be advanced past the largest uint64
+	layout = SnowflakeLayout{TimeBits: 64, SeqBits: 1, Epoch: epoch, Unit: time.Nanosecond}
+	if s, err = NewSnowflake(layout, 0, func() time.Time { return now }); err != nil {
+		t.Fatal(err)
+	}
+	s.started, s.lastTime, s.seq = true, 1<<64-1, 1
+	if x, err := s.Next(); err == nil {
+		t.Errorf("Expected Snowflake to overflow, got: %s", x)
+	}
+}
+
+func TestNewSnowflakeInvalid(t *testing.T) {
+	tests := []struct {
+		layout SnowflakeLayout
+		node   uint64
+	}{
+		{SnowflakeLayout{TimeBits: 65}, 0},
+		{SnowflakeLayout{TimeBits: 64, NodeBits: 32, SeqBits: 33}, 0},
+		{SnowflakeLayout{TimeBits: 64, NodeBits: 8, SeqBits: 8}, 256},
+	}
+	for _, test := range tests {
+		if _, err := NewSnowflake(test.layout, test.node, nil); err == nil {
+			t.Errorf("Expected NewSnowflake(%+v, %d) to fail", test.layout, test.node)
+		}
+	}
+}
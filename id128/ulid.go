@@ -0,0 +1,40 @@
+package id128
+
+import (
+	mrand "math/rand/v2"
+	"time"
+
+	"github.com/ryanavella/wide"
+)
+
+// ulidRandBits is the number of random bits which follow the 48-bit timestamp of a ULID
+const ulidRandBits = 80
+
+// ULIDGenerator generates ULIDs, which are a 48-bit Unix timestamp in milliseconds followed by 80 random bits
+//
+// Within the same millisecond, each ULID is the previous one incremented by 1, as in the monotonic mode of the ULID
+// specification. The zero value uses time.Now and crypto/rand.
+type ULIDGenerator struct {
+	m monotonic
+}
+
+// NewULIDGenerator returns a ULIDGenerator with the given clock and random source
+//
+// A nil clock defaults to time.Now, and a nil source defaults to crypto/rand.
+func NewULIDGenerator(now func() time.Time, src mrand.Source) *ULIDGenerator {
+	return &ULIDGenerator{m: monotonic{now: now, src: src}}
+}
+
+// ULIDTime returns the timestamp of a ULID
+func ULIDTime(x wide.Uint128) time.Time {
+	return time.UnixMilli(int64(x.RShiftN(ulidRandBits).Uint64()))
+}
+
+// Next returns a new ULID
+//
+// If the 80 random bits overflow within a millisecond, the increment carries into the timestamp rather than failing.
+func (g *ULIDGenerator) Next() wide.Uint128 {
+	return g.m.next(func(t time.Time) uint64 {
+		return uint64(t.UnixMilli()) & (1<<(128-ulidRandBits) - 1)
+	}, ulidRandBits)
+}
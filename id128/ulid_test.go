@@ -0,0 +1,69 @@
+package id128
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ryanavella/wide"
+)
+
+func TestULIDGenerator(t *testing.T) {
+	// The timestamp of the example ULID 01ARZ3NDEKTSV4RRFFQ69G5FAV, from the ULID specification
+	now := time.UnixMilli(1469922850259)
+	clock := func() time.Time { return now }
+	g1 := NewULIDGenerator(clock, wide.NewPCG64(wide.Uint128FromUint64(1), wide.Uint128{}))
+	g2 := NewULIDGenerator(clock, wide.NewPCG64(wide.Uint128FromUint64(1), wide.Uint128{}))
+	first := g1.Next()
+	if result := EncodeBase32(first)[:10]; result != "01ARZ3NDEK" {
+		t.Errorf("Expected ULID with timestamp 01ARZ3NDEK, got: %s", EncodeBase32(first))
+	}
+	if result := ULIDTime(first); !result.Equal(now) {
+		t.Errorf("Expected ULIDTime(%s) == %s, got: %s", EncodeBase32(first), now, result)
+	}
+	if g2.Next() != first {
+		t.Fatalf("Expected ULIDGenerator with the same clock and source to be deterministic")
+	}
+	last := first
+	for i := 1; i < 100; i++ {
+		if i == 50 {
+			// The clock goes backwards
+			now = now.Add(-time.Second)
+		}
+		x := g1.Next()
+		if x != last.Inc() {
+			t.Errorf("Expected ULID %s to follow %s, got: %s", EncodeBase32(last.Inc()), EncodeBase32(last), EncodeBase32(x))
+		}
+		last = x
+	}
+	now = now.Add(2 * time.Second)
+	if x := g1.Next(); !x.Gt(last) || !ULIDTime(x).Equal(now) {
+		t.Errorf("Expected a new ULID with timestamp %s, got: %s", now, EncodeBase32(x))
+	}
+}
+
+func TestULIDGeneratorConcurrent(t *testing.T) {
+	var g ULIDGenerator
+	const n, m = 8, 1000
+	results := make([][]wide.Uint128, n)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < m; j++ {
+				results[i] = append(results[i], g.Next())
+			}
+		}(i)
+	}
+	wg.Wait()
+	seen := make(map[wide.Uint128]bool)
+	for _, r := range results {
+		for j, x := range r {
+			if seen[x] || j > 0 && !x.Gt(r[j-1]) {
+				t.Fatalf("Expected unique and increasing ULIDs, got: %s", EncodeBase32(x))
+			}
+			seen[x] = true
+		}
+	}
+}
@@ -90,6 +90,11 @@ func (x Int128) AndNot(y Int128) (z Int128) {
 	return z
 }
 
+// Cbrt returns the cube root of an Int128, rounded toward zero
+func (x Int128) Cbrt() Int128 {
+	return x.NthRoot(3)
+}
+
 // Cmp compares x and y and returns:
 //
 //   -1 if x <  y
@@ -230,6 +235,11 @@ func (x Int128) IsNeg() bool {
 	return false
 }
 
+// IsPowerOfTwo returns whether x is a positive integer power of two
+func (x Int128) IsPowerOfTwo() bool {
+	return x.hi >= 0 && x.Uint128().IsPowerOfTwo()
+}
+
 // IsPos returns whether or not the Int128 is positive
 func (x Int128) IsPos() bool {
 	switch {
@@ -254,6 +264,26 @@ func (x Int128) Int64() int64 {
 	return int64(x.lo)
 }
 
+// Log10 returns the base 10 logarithm of an Int128, rounded down
+//
+// Log10 panics if x is not positive.
+func (x Int128) Log10() uint {
+	if x.hi < 0 {
+		panic("logarithm of negative number")
+	}
+	return x.Uint128().Log10()
+}
+
+// Log2 returns the base 2 logarithm of an Int128, rounded down
+//
+// Log2 panics if x is not positive.
+func (x Int128) Log2() uint {
+	if x.hi < 0 {
+		panic("logarithm of negative number")
+	}
+	return x.Uint128().Log2()
+}
+
 // LShift returns an Int128 left-shifted by 1
 func (x Int128) LShift() (z Int128) {
 	z.hi = int64(uint64(x.hi)<<1 | x.lo>>(int64Size-1))
@@ -380,6 +410,19 @@ func (x Int128) Not() (z Int128) {
 	return z
 }
 
+// NthRoot returns the n-th root of an Int128, rounded toward zero
+//
+// NthRoot panics if n is 0, or if n is even and x is negative.
+func (x Int128) NthRoot(n uint) Int128 {
+	if x.hi >= 0 {
+		return x.Uint128().NthRoot(n).Int128()
+	}
+	if n&1 == 0 {
+		panic("even root of negative number")
+	}
+	return x.Neg().Uint128().NthRoot(n).Int128().Neg()
+}
+
 // Or returns the bitwise OR of two Int128's
 func (x Int128) Or(y Int128) (z Int128) {
 	z.hi = x.hi | y.hi
@@ -387,6 +430,25 @@ func (x Int128) Or(y Int128) (z Int128) {
 	return z
 }
 
+// Pow returns x raised to the power n
+//
+// This function overflows silently
+func (x Int128) Pow(n uint) Int128 {
+	return x.Uint128().Pow(n).Int128()
+}
+
+// PowChecked returns x raised to the power n, and whether the result was computed without overflowing
+func (x Int128) PowChecked(n uint) (z Int128, ok bool) {
+	neg := x.hi < 0 && n&1 != 0
+	y, ok := x.Abs().Uint128().PowChecked(n)
+	z = y.Int128()
+	if neg {
+		// -(2^127) is representable even though +(2^127) is not
+		return z.Neg(), ok && (y.hi <= maxInt64 || y.hi == 1<<63 && y.lo == 0)
+	}
+	return z, ok && y.hi <= maxInt64
+}
+
 // RShift returns an Int128 right-shifted by 1
 func (x Int128) RShift() (z Int128) {
 	xhi := uint64(x.hi)
@@ -441,6 +503,16 @@ func (x Int128) Sign() int {
 	return 0
 }
 
+// Sqrt returns the square root of an Int128, rounded down
+//
+// Sqrt panics if x is negative.
+func (x Int128) Sqrt() Int128 {
+	if x.hi < 0 {
+		panic("square root of negative number")
+	}
+	return x.Uint128().Sqrt().Int128()
+}
+
 // Sub returns the difference of two Int128's
 func (x Int128) Sub(y Int128) (z Int128) {
 	z.hi = x.hi - y.hi
@@ -451,6 +523,14 @@ func (x Int128) Sub(y Int128) (z Int128) {
 	return z
 }
 
+// TrailingZeros returns the number of trailing zero bits in the two's complement representation of x
+//
+// Edge cases:
+//   Int128{0, 0}.TrailingZeros() -> 128
+func (x Int128) TrailingZeros() uint {
+	return x.Uint128().TrailingZeros()
+}
+
 // Uint128 returns a Uint128 representation of an Int128
 //
 // This function overflows silently
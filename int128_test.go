@@ -85,6 +85,25 @@ func TestAndNotInt128(t *testing.T) {
 	}
 }
 
+func TestCbrtInt128(t *testing.T) {
+	tests := []struct {
+		inp      Int128
+		expected Int128
+	}{
+		{Int128{hi: 0, lo: 0}, Int128{hi: 0, lo: 0}},
+		{Int128{hi: 0, lo: 27}, Int128{hi: 0, lo: 3}},
+		{Int128{hi: -1, lo: maxUint64 - 26}, Int128{hi: -1, lo: maxUint64 - 2}},
+		{Int128{hi: -1, lo: maxUint64 - 25}, Int128{hi: -1, lo: maxUint64 - 1}},
+		{Int128{hi: minInt64, lo: 0}, Int128FromInt64(-5541191377756)},
+	}
+	for _, test := range tests {
+		result := test.inp.Cbrt()
+		if !result.Eq(test.expected) {
+			t.Errorf("Expected %s.Cbrt() == %s, got: %s", test.inp, test.expected, result)
+		}
+	}
+}
+
 func TestCmpInt128(t *testing.T) {
 	tests := []struct {
 		op1      Int128
@@ -270,6 +289,27 @@ func TestIsInt64Int128(t *testing.T) {
 	}
 }
 
+func TestIsPowerOfTwoInt128(t *testing.T) {
+	tests := []struct {
+		inp      Int128
+		expected bool
+	}{
+		{Int128{hi: 0, lo: 0}, false},
+		{Int128{hi: 0, lo: 1}, true},
+		{Int128{hi: 0, lo: 6}, false},
+		{Int128{hi: 1 << 62, lo: 0}, true},
+		{Int128{hi: -1, lo: maxUint64}, false},
+		{Int128{hi: -1, lo: maxUint64 - 1}, false},
+		{Int128{hi: minInt64, lo: 0}, false},
+	}
+	for _, test := range tests {
+		result := test.inp.IsPowerOfTwo()
+		if test.expected != result {
+			t.Errorf("Expected %s.IsPowerOfTwo() == %v, got: %v", test.inp, test.expected, result)
+		}
+	}
+}
+
 func TestIsUint64Int128(t *testing.T) {
 	tests := []struct {
 		inp      Int128
@@ -310,6 +350,49 @@ func TestInt64Int128(t *testing.T) {
 	}
 }
 
+func TestLog10Int128(t *testing.T) {
+	tests := []struct {
+		inp      Int128
+		expected uint
+	}{
+		{Int128{hi: 0, lo: 1}, 0},
+		{Int128{hi: 0, lo: 1000}, 3},
+		{Int128{hi: maxInt64, lo: maxUint64}, 38},
+	}
+	for _, test := range tests {
+		result := test.inp.Log10()
+		if test.expected != result {
+			t.Errorf("Expected %s.Log10() == %v, got: %v", test.inp, test.expected, result)
+		}
+	}
+}
+
+func TestLog2Int128(t *testing.T) {
+	tests := []struct {
+		inp      Int128
+		expected uint
+	}{
+		{Int128{hi: 0, lo: 1}, 0},
+		{Int128{hi: 0, lo: 1000}, 9},
+		{Int128{hi: maxInt64, lo: maxUint64}, 126},
+	}
+	for _, test := range tests {
+		result := test.inp.Log2()
+		if test.expected != result {
+			t.Errorf("Expected %s.Log2() == %v, got: %v", test.inp, test.expected, result)
+		}
+	}
+}
+
+func TestLog2NegInt128(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Logarithm of a negative number did not panic")
+		}
+	}()
+	Int128{hi: -1, lo: maxUint64}.Log2()
+}
+
 func TestLShiftInt128(t *testing.T) {
 	tests := []struct {
 		inp      Int128
@@ -485,6 +568,35 @@ func TestNotInt128(t *testing.T) {
 	}
 }
 
+func TestNthRootInt128(t *testing.T) {
+	tests := []struct {
+		op1      Int128
+		op2      uint
+		expected Int128
+	}{
+		{Int128{hi: 0, lo: 32}, 5, Int128{hi: 0, lo: 2}},
+		{Int128FromInt64(-32), 5, Int128FromInt64(-2)},
+		{Int128FromInt64(-31), 5, Int128FromInt64(-1)},
+		{Int128{hi: maxInt64, lo: maxUint64}, 127, Int128{hi: 0, lo: 1}},
+		{Int128{hi: minInt64, lo: 0}, 127, Int128FromInt64(-2)},
+	}
+	for _, test := range tests {
+		result := test.op1.NthRoot(test.op2)
+		if !result.Eq(test.expected) {
+			t.Errorf("Expected %s.NthRoot(%v) == %s, got: %s", test.op1, test.op2, test.expected, result)
+		}
+	}
+}
+
+func TestNthRootNegInt128(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Even root of a negative number did not panic")
+		}
+	}()
+	Int128{hi: -1, lo: maxUint64}.NthRoot(4)
+}
+
 func TestOrInt128(t *testing.T) {
 	tests := []struct {
 		op1      Int128
@@ -504,6 +616,51 @@ func TestOrInt128(t *testing.T) {
 	}
 }
 
+func TestPowInt128(t *testing.T) {
+	tests := []struct {
+		op1      Int128
+		op2      uint
+		expected Int128
+	}{
+		{Int128{hi: 0, lo: 3}, 4, Int128{hi: 0, lo: 81}},
+		{Int128FromInt64(-3), 3, Int128FromInt64(-27)},
+		{Int128FromInt64(-3), 4, Int128{hi: 0, lo: 81}},
+		{Int128{hi: 0, lo: 2}, 127, Int128{hi: minInt64, lo: 0}},
+		{Int128FromInt64(-2), 127, Int128{hi: minInt64, lo: 0}},
+		{Int128FromInt64(-2), 128, Int128{hi: 0, lo: 0}},
+	}
+	for _, test := range tests {
+		result := test.op1.Pow(test.op2)
+		if !result.Eq(test.expected) {
+			t.Errorf("Expected %s.Pow(%v) == %s, got: %s", test.op1, test.op2, test.expected, result)
+		}
+	}
+}
+
+func TestPowCheckedInt128(t *testing.T) {
+	tests := []struct {
+		op1       Int128
+		op2       uint
+		expected1 Int128
+		expected2 bool
+	}{
+		{Int128{hi: 0, lo: 3}, 4, Int128{hi: 0, lo: 81}, true},
+		{Int128FromInt64(-3), 3, Int128FromInt64(-27), true},
+		{Int128{hi: 0, lo: 2}, 126, Int128{hi: 1 << 62, lo: 0}, true},
+		{Int128{hi: 0, lo: 2}, 127, Int128{}, false},
+		{Int128FromInt64(-2), 127, Int128{hi: minInt64, lo: 0}, true},
+		{Int128FromInt64(-2), 128, Int128{}, false},
+		{Int128{hi: minInt64, lo: 0}, 1, Int128{hi: minInt64, lo: 0}, true},
+		{Int128{hi: minInt64, lo: 0}, 2, Int128{}, false},
+	}
+	for _, test := range tests {
+		result1, result2 := test.op1.PowChecked(test.op2)
+		if result2 != test.expected2 || result2 && !result1.Eq(test.expected1) {
+			t.Errorf("Expected %s.PowChecked(%v) == %s, %v got: %s, %v", test.op1, test.op2, test.expected1, test.expected2, result1, result2)
+		}
+	}
+}
+
 func TestRShiftInt128(t *testing.T) {
 	tests := []struct {
 		inp      Int128
@@ -561,6 +718,32 @@ func TestRShiftNInt128(t *testing.T) {
 	}
 }
 
+func TestSqrtInt128(t *testing.T) {
+	tests := []struct {
+		inp      Int128
+		expected Int128
+	}{
+		{Int128{hi: 0, lo: 0}, Int128{hi: 0, lo: 0}},
+		{Int128{hi: 0, lo: 99}, Int128{hi: 0, lo: 9}},
+		{Int128{hi: maxInt64, lo: maxUint64}, Int128{hi: 0, lo: 0xb504f333f9de6484}},
+	}
+	for _, test := range tests {
+		result := test.inp.Sqrt()
+		if !result.Eq(test.expected) {
+			t.Errorf("Expected %s.Sqrt() == %s, got: %s", test.inp, test.expected, result)
+		}
+	}
+}
+
+func TestSqrtNegInt128(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Square root of a negative number did not panic")
+		}
+	}()
+	Int128{hi: -1, lo: maxUint64}.Sqrt()
+}
+
 func TestSubInt128(t *testing.T) {
 	tests := []struct {
 		expected Int128
@@ -586,6 +769,24 @@ func TestSubInt128(t *testing.T) {
 	}
 }
 
+func TestTrailingZerosInt128(t *testing.T) {
+	tests := []struct {
+		inp      Int128
+		expected uint
+	}{
+		{Int128{hi: 0, lo: 0}, 128},
+		{Int128FromInt64(-1), 0},
+		{Int128FromInt64(-8), 3},
+		{Int128{hi: minInt64, lo: 0}, 127},
+	}
+	for _, test := range tests {
+		result := test.inp.TrailingZeros()
+		if test.expected != result {
+			t.Errorf("Expected %s.TrailingZeros() == %v, got: %v", test.inp, test.expected, result)
+		}
+	}
+}
+
 func TestUint64Int128(t *testing.T) {
 	tests := []struct {
 		inp      Int128
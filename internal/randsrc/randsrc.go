@@ -0,0 +1,19 @@
+// Package randsrc provides a random source for math/rand/v2 which reads from crypto/rand.
+package randsrc
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+)
+
+// Crypto is a random source which reads from crypto/rand
+type Crypto struct{}
+
+// Uint64 returns a cryptographically secure random uint64
+func (Crypto) Uint64() uint64 {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	return binary.LittleEndian.Uint64(b[:])
+}
@@ -0,0 +1,197 @@
+package wide
+
+import (
+	"iter"
+	"slices"
+	"sort"
+)
+
+// maxUint128 is the largest Uint128
+var maxUint128 = Uint128{hi: maxUint64, lo: maxUint64}
+
+// IntervalSet128 is a set of Uint128's, stored as sorted, disjoint and non-adjacent closed ranges [lo, hi]
+//
+// Adjacent ranges are coalesced, so [1, 2] and [3, 4] are stored as [1, 4]. The zero value is an empty set.
+type IntervalSet128 struct {
+	ranges []interval128
+}
+
+// interval128 is a closed range [lo, hi] of Uint128's
+type interval128 struct {
+	lo, hi Uint128
+}
+
+// Add adds the range [lo, hi] to an IntervalSet128, merging it with any ranges which it overlaps or is adjacent to
+//
+// Add does nothing if lo > hi.
+func (s *IntervalSet128) Add(lo, hi Uint128) {
+	if lo.Gt(hi) {
+		return
+	}
+	rs := s.ranges
+	// The ranges in [i, j) overlap or are adjacent to [lo, hi]
+	i := sort.Search(len(rs), func(i int) bool { return rs[i].hi == maxUint128 || rs[i].hi.Inc().Gte(lo) })
+	j := sort.Search(len(rs), func(j int) bool { return rs[j].lo.Gt(hi) && rs[j].lo.Dec().Gt(hi) })
+	if i < j {
+		lo = lesserUint128(lo, rs[i].lo)
+		hi = greaterUint128(hi, rs[j-1].hi)
+	}
+	s.ranges = slices.Replace(rs, i, j, interval128{lo, hi})
+}
+
+// Complement returns the set of Uint128's which are not in an IntervalSet128
+func (s *IntervalSet128) Complement() *IntervalSet128 {
+	c := new(IntervalSet128)
+	next, done := Uint128{}, false
+	for _, r := range s.ranges {
+		if r.lo.Gt(next) {
+			c.ranges = append(c.ranges, interval128{next, r.lo.Dec()})
+		}
+		if r.hi == maxUint128 {
+			done = true
+			break
+		}
+		next = r.hi.Inc()
+	}
+	if !done {
+		c.ranges = append(c.ranges, interval128{next, maxUint128})
+	}
+	return c
+}
+
+// Contains returns whether x is in an IntervalSet128
+func (s *IntervalSet128) Contains(x Uint128) bool {
+	i := s.search(x)
+	return i < len(s.ranges) && s.ranges[i].lo.Lte(x)
+}
+
+// ContainsRange returns whether every Uint128 in [lo, hi] is in an IntervalSet128
+//
+// An empty range, with lo > hi, is always contained.
+func (s *IntervalSet128) ContainsRange(lo, hi Uint128) bool {
+	if lo.Gt(hi) {
+		return true
+	}
+	i := s.search(lo)
+	return i < len(s.ranges) && s.ranges[i].lo.Lte(lo) && s.ranges[i].hi.Gte(hi)
+}
+
+// Intersect returns the set of Uint128's which are in both IntervalSet128's
+func (s *IntervalSet128) Intersect(t *IntervalSet128) *IntervalSet128 {
+	z := new(IntervalSet128)
+	a, b := s.ranges, t.ranges
+	for len(a) > 0 && len(b) > 0 {
+		lo, hi := greaterUint128(a[0].lo, b[0].lo), lesserUint128(a[0].hi, b[0].hi)
+		if lo.Lte(hi) {
+			z.ranges = append(z.ranges, interval128{lo, hi})
+		}
+		if a[0].hi.Lt(b[0].hi) {
+			a = a[1:]
+		} else {
+			b = b[1:]
+		}
+	}
+	return z
+}
+
+// Iterate returns an iterator over the ranges [lo, hi] of an IntervalSet128, in ascending order
+func (s *IntervalSet128) Iterate() iter.Seq2[Uint128, Uint128] {
+	return func(yield func(lo, hi Uint128) bool) {
+		for _, r := range s.ranges {
+			if !yield(r.lo, r.hi) {
+				return
+			}
+		}
+	}
+}
+
+// Len returns the number of ranges in an IntervalSet128
+func (s *IntervalSet128) Len() int {
+	return len(s.ranges)
+}
+
+// Overlaps returns whether any Uint128 in [lo, hi] is in an IntervalSet128
+func (s *IntervalSet128) Overlaps(lo, hi Uint128) bool {
+	if lo.Gt(hi) {
+		return false
+	}
+	i := s.search(lo)
+	return i < len(s.ranges) && s.ranges[i].lo.Lte(hi)
+}
+
+// Remove removes the range [lo, hi] from an IntervalSet128, splitting any range which contains it
+//
+// Remove does nothing if lo > hi.
+func (s *IntervalSet128) Remove(lo, hi Uint128) {
+	if lo.Gt(hi) {
+		return
+	}
+	rs := s.ranges
+	// The ranges in [i, j) overlap [lo, hi]
+	i := s.search(lo)
+	j := sort.Search(len(rs), func(j int) bool { return rs[j].lo.Gt(hi) })
+	if i >= j {
+		return
+	}
+	var keep []interval128
+	if rs[i].lo.Lt(lo) {
+		keep = append(keep, interval128{rs[i].lo, lo.Dec()})
+	}
+	if rs[j-1].hi.Gt(hi) {
+		keep = append(keep, interval128{hi.Inc(), rs[j-1].hi})
+	}
+	s.ranges = slices.Replace(rs, i, j, keep...)
+}
+
+// search returns the index of the first range of an IntervalSet128 which ends at or after x
+func (s *IntervalSet128) search(x Uint128) int {
+	rs := s.ranges
+	return sort.Search(len(rs), func(i int) bool { return rs[i].hi.Gte(x) })
+}
+
+// Size returns the number of Uint128's in an IntervalSet128
+//
+// The set of every Uint128 has 2^128 elements, which does not fit in a Uint128, so Size returns 0 and full == true for it.
+func (s *IntervalSet128) Size() (n Uint128, full bool) {
+	if len(s.ranges) == 1 && s.ranges[0] == (interval128{Uint128{}, maxUint128}) {
+		return Uint128{}, true
+	}
+	for _, r := range s.ranges {
+		n = n.Add(r.hi.Sub(r.lo).Inc())
+	}
+	return n, false
+}
+
+// Subtract returns the set of Uint128's which are in s but not in t
+func (s *IntervalSet128) Subtract(t *IntervalSet128) *IntervalSet128 {
+	z := &IntervalSet128{ranges: slices.Clone(s.ranges)}
+	for _, r := range t.ranges {
+		z.Remove(r.lo, r.hi)
+	}
+	return z
+}
+
+// Union returns the set of Uint128's which are in either IntervalSet128
+func (s *IntervalSet128) Union(t *IntervalSet128) *IntervalSet128 {
+	z := &IntervalSet128{ranges: slices.Clone(s.ranges)}
+	for _, r := range t.ranges {
+		z.Add(r.lo, r.hi)
+	}
+	return z
+}
+
+// greaterUint128 returns the larger of two Uint128's
+func greaterUint128(x, y Uint128) Uint128 {
+	if x.Gt(y) {
+		return x
+	}
+	return y
+}
+
+// lesserUint128 returns the smaller of two Uint128's
+func lesserUint128(x, y Uint128) Uint128 {
+	if x.Lt(y) {
+		return x
+	}
+	return y
+}
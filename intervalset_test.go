@@ -0,0 +1,113 @@
+package wide
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+// intervalSetModel is a bitmap model of an IntervalSet128 over a small universe
+type intervalSetModel [256]bool
+
+// checkIntervalSet compares an IntervalSet128 over [0, 256) to its model, and checks that its ranges are sorted and coalesced
+func checkIntervalSet(t *testing.T, s *IntervalSet128, m *intervalSetModel) {
+	t.Helper()
+	var prev Uint128
+	first := true
+	for lo, hi := range s.Iterate() {
+		if lo.Gt(hi) || !first && prev.Inc().Gte(lo) {
+			t.Fatalf("Expected sorted, disjoint and non-adjacent ranges, got: [%s, %s] after %s", lo, hi, prev)
+		}
+		prev, first = hi, false
+	}
+	count := 0
+	for x := range m {
+		if m[x] {
+			count++
+		}
+		if s.Contains(Uint128{lo: uint64(x)}) != m[x] {
+			t.Fatalf("Expected IntervalSet128.Contains(%d) == %t", x, m[x])
+		}
+	}
+	if n, full := s.Size(); n != (Uint128{lo: uint64(count)}) || full {
+		t.Fatalf("Expected IntervalSet128.Size() == %d, false, got: %s, %t", count, n, full)
+	}
+}
+
+func TestIntervalSet128(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	var s, u IntervalSet128
+	var m, mu intervalSetModel
+	for i := 0; i < 2000; i++ {
+		lo, hi := r.Uint64N(256), r.Uint64N(256)
+		if r.IntN(4) != 0 && lo > hi {
+			lo, hi = hi, lo
+		}
+		switch r.IntN(3) {
+		case 0, 1:
+			s.Add(Uint128{lo: lo}, Uint128{lo: hi})
+			for x := lo; x <= hi; x++ {
+				m[x] = true
+			}
+		default:
+			s.Remove(Uint128{lo: lo}, Uint128{lo: hi})
+			for x := lo; x <= hi; x++ {
+				m[x] = false
+			}
+		}
+		checkIntervalSet(t, &s, &m)
+		overlaps, contains := false, lo <= hi
+		for x := lo; x <= hi; x++ {
+			overlaps = overlaps || m[x]
+			contains = contains && m[x]
+		}
+		if lo > hi {
+			contains = true
+		}
+		if s.Overlaps(Uint128{lo: lo}, Uint128{lo: hi}) != overlaps || s.ContainsRange(Uint128{lo: lo}, Uint128{lo: hi}) != contains {
+			t.Fatalf("Expected IntervalSet128.Overlaps(%d, %d) == %t and ContainsRange == %t", lo, hi, overlaps, contains)
+		}
+		if i%100 == 0 {
+			// Combine with a second random set
+			x, y := r.Uint64N(256), r.Uint64N(256)
+			u.Add(Uint128{lo: min(x, y)}, Uint128{lo: max(x, y)})
+			for v := min(x, y); v <= max(x, y); v++ {
+				mu[v] = true
+			}
+			var union, inter, diff intervalSetModel
+			for v := range m {
+				union[v], inter[v], diff[v] = m[v] || mu[v], m[v] && mu[v], m[v] && !mu[v]
+			}
+			checkIntervalSet(t, s.Union(&u), &union)
+			checkIntervalSet(t, s.Intersect(&u), &inter)
+			checkIntervalSet(t, s.Subtract(&u), &diff)
+		}
+	}
+}
+
+func TestIntervalSet128Full(t *testing.T) {
+	var s IntervalSet128
+	if n, full := s.Complement().Size(); n != (Uint128{}) || !full {
+		t.Errorf("Expected the complement of the empty set to be full, got: %s, %t", n, full)
+	}
+	s.Add(maxUint128, maxUint128)
+	s.Add(Uint128{}, Uint128{lo: 1})
+	if n, full := s.Size(); n != (Uint128{lo: 3}) || full {
+		t.Errorf("Expected IntervalSet128.Size() == 0x3, false, got: %s, %t", n, full)
+	}
+	c := s.Complement()
+	if c.Len() != 1 || !c.ContainsRange(Uint128{lo: 2}, maxUint128.Dec()) || c.Contains(maxUint128) {
+		t.Errorf("Expected the complement to be [0x2, 2^128 - 2]")
+	}
+	s.Add(Uint128{lo: 2}, maxUint128.Dec())
+	if n, full := s.Size(); n != (Uint128{}) || !full || s.Len() != 1 {
+		t.Errorf("Expected IntervalSet128 to coalesce into the full set, got: %s, %t", n, full)
+	}
+	if c := s.Complement(); c.Len() != 0 {
+		t.Errorf("Expected the complement of the full set to be empty, got %d ranges", c.Len())
+	}
+	s.Remove(Uint128{}, Uint128{})
+	s.Remove(maxUint128, maxUint128)
+	if n, full := s.Size(); n != maxUint128.Dec() || full {
+		t.Errorf("Expected IntervalSet128.Size() == %s, false, got: %s, %t", maxUint128.Dec(), n, full)
+	}
+}
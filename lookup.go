@@ -0,0 +1,52 @@
+package wide
+
+// pow10tab holds the powers of ten which are representable as a Uint128
+var pow10tab = [39]Uint128{
+	{hi: 0x0, lo: 0x1},                              // 1e0
+	{hi: 0x0, lo: 0xa},                              // 1e1
+	{hi: 0x0, lo: 0x64},                             // 1e2
+	{hi: 0x0, lo: 0x3e8},                            // 1e3
+	{hi: 0x0, lo: 0x2710},                           // 1e4
+	{hi: 0x0, lo: 0x186a0},                          // 1e5
+	{hi: 0x0, lo: 0xf4240},                          // 1e6
+	{hi: 0x0, lo: 0x989680},                         // 1e7
+	{hi: 0x0, lo: 0x5f5e100},                        // 1e8
+	{hi: 0x0, lo: 0x3b9aca00},                       // 1e9
+	{hi: 0x0, lo: 0x2540be400},                      // 1e10
+	{hi: 0x0, lo: 0x174876e800},                     // 1e11
+	{hi: 0x0, lo: 0xe8d4a51000},                     // 1e12
+	{hi: 0x0, lo: 0x9184e72a000},                    // 1e13
+	{hi: 0x0, lo: 0x5af3107a4000},                   // 1e14
+	{hi: 0x0, lo: 0x38d7ea4c68000},                  // 1e15
+	{hi: 0x0, lo: 0x2386f26fc10000},                 // 1e16
+	{hi: 0x0, lo: 0x16345785d8a0000},                // 1e17
+	{hi: 0x0, lo: 0xde0b6b3a7640000},                // 1e18
+	{hi: 0x0, lo: 0x8ac7230489e80000},               // 1e19
+	{hi: 0x5, lo: 0x6bc75e2d63100000},               // 1e20
+	{hi: 0x36, lo: 0x35c9adc5dea00000},              // 1e21
+	{hi: 0x21e, lo: 0x19e0c9bab2400000},             // 1e22
+	{hi: 0x152d, lo: 0x2c7e14af6800000},             // 1e23
+	{hi: 0xd3c2, lo: 0x1bcecceda1000000},            // 1e24
+	{hi: 0x84595, lo: 0x161401484a000000},           // 1e25
+	{hi: 0x52b7d2, lo: 0xdcc80cd2e4000000},          // 1e26
+	{hi: 0x33b2e3c, lo: 0x9fd0803ce8000000},         // 1e27
+	{hi: 0x204fce5e, lo: 0x3e25026110000000},        // 1e28
+	{hi: 0x1431e0fae, lo: 0x6d7217caa0000000},       // 1e29
+	{hi: 0xc9f2c9cd0, lo: 0x4674edea40000000},       // 1e30
+	{hi: 0x7e37be2022, lo: 0xc0914b2680000000},      // 1e31
+	{hi: 0x4ee2d6d415b, lo: 0x85acef8100000000},     // 1e32
+	{hi: 0x314dc6448d93, lo: 0x38c15b0a00000000},    // 1e33
+	{hi: 0x1ed09bead87c0, lo: 0x378d8e6400000000},   // 1e34
+	{hi: 0x13426172c74d82, lo: 0x2b878fe800000000},  // 1e35
+	{hi: 0xc097ce7bc90715, lo: 0xb34b9f1000000000},  // 1e36
+	{hi: 0x785ee10d5da46d9, lo: 0xf436a000000000},   // 1e37
+	{hi: 0x4b3b4ca85a86c47a, lo: 0x98a224000000000}, // 1e38
+}
+
+// smallPrimes holds the primes below 256, which are used for trial division
+var smallPrimes = [...]uint64{
+	2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37, 41, 43, 47, 53,
+	59, 61, 67, 71, 73, 79, 83, 89, 97, 101, 103, 107, 109, 113, 127, 131,
+	137, 139, 149, 151, 157, 163, 167, 173, 179, 181, 191, 193, 197, 199, 211, 223,
+	227, 229, 233, 239, 241, 251,
+}
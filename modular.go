@@ -0,0 +1,192 @@
+package wide
+
+// divMod256 returns the quotient and remainder of the 256-bit dividend (hi, lo) divided by d
+//
+// The high half of the dividend must be less than d, so that the quotient fits in a Uint128. This is a long division with
+// 64-bit digits (Knuth, TAOCP vol. 2, section 4.3.1, algorithm D), where each digit of the quotient is found by div192by128.
+func divMod256(hi, lo, d Uint128) (q, r Uint128) {
+	if d.hi == 0 {
+		// hi < d, so hi fits in 64 bits, and two 128/64 divisions suffice
+		var rem uint64
+		q.hi, rem = div128by64(hi.lo, lo.hi, d.lo)
+		q.lo, r.lo = div128by64(rem, lo.lo, d.lo)
+		return q, r
+	}
+	// Normalize d so that its most significant bit is set, which bounds the error of each estimated quotient digit
+	s := int128Size - d.Len()
+	if s != 0 {
+		d = d.LShiftN(s)
+		hi = hi.LShiftN(s).Or(lo.RShiftN(int128Size - s))
+		lo = lo.LShiftN(s)
+	}
+	q.hi, r = div192by128(hi.hi, hi.lo, lo.hi, d)
+	q.lo, r = div192by128(r.hi, r.lo, lo.lo, d)
+	return q, r.RShiftN(s)
+}
+
+// div192by128 returns the 64-bit quotient and the remainder of the 192-bit (u2, u1, u0) divided by d
+//
+// d must be normalized (i.e. its most significant bit is set), and (u2, u1) must be less than d.
+func div192by128(u2, u1, u0 uint64, d Uint128) (q uint64, r Uint128) {
+	// Estimate q from the leading digits, which is never too small, and at most 2 too large
+	var rhat uint64
+	overflow := false
+	if u2 < d.hi {
+		q, rhat = div128by64(u2, u1, d.hi)
+	} else {
+		// u2 == d.hi, so the estimate is clamped to the largest digit
+		q = maxUint64
+		rhat = u1 + d.hi
+		overflow = rhat < u1
+	}
+	// Since d has only two digits, this correction makes q exact
+	for !overflow && (Uint128{lo: q}).Mul(Uint128{lo: d.lo}).Gt(Uint128{hi: rhat, lo: u0}) {
+		q--
+		rhat += d.hi
+		overflow = rhat < d.hi
+	}
+	// The remainder is less than d, so it is determined by the lower 128 bits of the dividend and of q * d
+	return q, Uint128{hi: u1, lo: u0}.Sub(Uint128{lo: q}.Mul(d))
+}
+
+// mod256 returns the remainder of the 256-bit dividend (hi, lo) modulo m
+//
+// mod256 panics on division by 0.
+func mod256(hi, lo, m Uint128) (r Uint128) {
+	_, r = divMod256(hi.Mod(m), lo, m)
+	return r
+}
+
+// AddMod returns the sum of two Uint128's modulo m
+//
+// AddMod panics if m is 0.
+func (x Uint128) AddMod(y, m Uint128) (z Uint128) {
+	x, y = x.Mod(m), y.Mod(m)
+	z = x.Add(y)
+	if z.Lt(x) || z.Gte(m) {
+		z = z.Sub(m)
+	}
+	return z
+}
+
+// ModInverse returns the multiplicative inverse of x modulo m, and whether such an inverse exists
+//
+// ModInverse panics if m is 0. The inverse is found with the extended Euclidean algorithm.
+func (x Uint128) ModInverse(m Uint128) (z Uint128, ok bool) {
+	// The Bézout coefficients of x alternate in sign, so only their magnitudes are tracked (neg is the sign of t1)
+	r0, r1 := m, x.Mod(m)
+	t0, t1 := Uint128{}, Uint128{lo: 1}
+	neg := false
+	for r1.hi != 0 || r1.lo != 0 {
+		q, r := r0.DivMod(r1)
+		r0, r1 = r1, r
+		t0, t1 = t1, t0.Add(q.Mul(t1))
+		neg = !neg
+	}
+	if r0.hi != 0 || r0.lo != 1 {
+		return z, false
+	}
+	if !neg && (t0.hi != 0 || t0.lo != 0) {
+		return m.Sub(t0), true
+	}
+	return t0, true
+}
+
+// ModSqrt returns a square root of x modulo p, and whether such a square root exists
+//
+// The modulus p must be prime, otherwise the result is undefined, although ModSqrt still returns, and any root it returns
+// is correct. ModSqrt panics if p is 0. Tonelli-Shanks is used when p = 1 (mod 4),
+// otherwise the root is found with a single exponentiation.
+func (x Uint128) ModSqrt(p Uint128) (z Uint128, ok bool) {
+	one := Uint128{lo: 1}
+	x = x.Mod(p)
+	switch {
+	case x.hi == 0 && x.lo == 0:
+		return z, true
+	case p.hi == 0 && p.lo == 2:
+		return x, true
+	case !x.PowMod(p.RShift(), p).Eq(one): // Euler's criterion
+		return z, false
+	case p.lo&3 == 3:
+		// (p+1)/4, without overflowing
+		return x.PowMod(p.RShiftN(2).Inc(), p), true
+	}
+	// p-1 = q*2^s, with q odd
+	q := p.Dec()
+	var s uint
+	for q.lo&1 == 0 {
+		q = q.RShift()
+		s++
+	}
+	// Find a quadratic non-residue
+	pm1 := p.Dec()
+	n := Uint128{lo: 2}
+	for {
+		e := n.PowMod(p.RShift(), p)
+		if e.Eq(pm1) {
+			break
+		}
+		// For a prime, Euler's criterion is always 1 or p-1, and some non-residue is less than p, so p is composite
+		if n = n.Inc(); !e.Eq(one) || n.Gte(p) {
+			return Uint128{}, false
+		}
+	}
+	c := n.PowMod(q, p)
+	z = x.PowMod(q.RShift().Inc(), p)
+	t := x.PowMod(q, p)
+	for !t.Eq(one) {
+		// Find the least i such that t^(2^i) = 1
+		var i uint
+		for tt := t; !tt.Eq(one); i++ {
+			if i == s {
+				// t^(2^s) = 1 holds for every prime, so p is composite
+				return Uint128{}, false
+			}
+			tt = tt.MulMod(tt, p)
+		}
+		b := c
+		for j := uint(0); j < s-i-1; j++ {
+			b = b.MulMod(b, p)
+		}
+		z = z.MulMod(b, p)
+		c = b.MulMod(b, p)
+		t = t.MulMod(c, p)
+		s = i
+	}
+	return z, true
+}
+
+// MulMod returns the product of two Uint128's modulo m
+//
+// MulMod panics if m is 0. The product is computed in full (256 bits) before it is reduced, so it never overflows.
+func (x Uint128) MulMod(y, m Uint128) Uint128 {
+	hi, lo := x.mulFull(y)
+	return mod256(hi, lo, m)
+}
+
+// PowMod returns x raised to the power e, modulo m
+//
+// PowMod panics if m is 0.
+func (x Uint128) PowMod(e, m Uint128) (z Uint128) {
+	z = Uint128{lo: 1}.Mod(m)
+	x = x.Mod(m)
+	for i := e.Len(); i > 0; i-- {
+		z = z.MulMod(z, m)
+		if e.RShiftN(i-1).lo&1 != 0 {
+			z = z.MulMod(x, m)
+		}
+	}
+	return z
+}
+
+// SubMod returns the difference of two Uint128's modulo m
+//
+// SubMod panics if m is 0.
+func (x Uint128) SubMod(y, m Uint128) (z Uint128) {
+	x, y = x.Mod(m), y.Mod(m)
+	z = x.Sub(y)
+	if x.Lt(y) {
+		z = z.Add(m)
+	}
+	return z
+}
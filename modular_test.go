@@ -0,0 +1,187 @@
+package wide
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+// randModUint128 returns a random Uint128 with a random bit length
+func randModUint128(r *rand.Rand) Uint128 {
+	return Uint128{hi: r.Uint64(), lo: r.Uint64()}.RShiftN(uint(r.Intn(int128Size)))
+}
+
+func TestAddModUint128(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		x, y, m := randModUint128(r), randModUint128(r), randModUint128(r).Inc()
+		result := bigUint128(x.AddMod(y, m))
+		expected := new(big.Int).Add(bigUint128(x), bigUint128(y))
+		expected.Mod(expected, bigUint128(m))
+		if result.Cmp(expected) != 0 {
+			t.Errorf("Expected %s.AddMod(%s, %s) == %#x, got: %#x", x, y, m, expected, result)
+		}
+	}
+}
+
+func TestDivMod256(t *testing.T) {
+	// Digits near the edges exercise the clamped and corrected estimates of each quotient digit
+	words := []uint64{0, 1, 2, 1<<63 - 1, 1 << 63, maxUint64 - 1, maxUint64}
+	var divisors []Uint128
+	for _, dhi := range words {
+		for _, dlo := range words {
+			if d := (Uint128{hi: dhi, lo: dlo}); d.hi != 0 || d.lo != 0 {
+				divisors = append(divisors, d)
+			}
+		}
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		divisors = append(divisors, randModUint128(r).Inc())
+	}
+	for _, d := range divisors {
+		for i := 0; i < 20; i++ {
+			var hi, lo Uint128
+			if i < 8 {
+				hi, lo = d.Dec(), Uint128{hi: words[r.Intn(len(words))], lo: words[r.Intn(len(words))]}
+			} else {
+				hi, lo = randModUint128(r).Mod(d), Uint128{hi: r.Uint64(), lo: r.Uint64()}
+			}
+			q, rem := divMod256(hi, lo, d)
+			n := new(big.Int).Lsh(bigUint128(hi), int128Size)
+			n.Add(n, bigUint128(lo))
+			bq, br := n.QuoRem(n, bigUint128(d), new(big.Int))
+			if bigUint128(q).Cmp(bq) != 0 || bigUint128(rem).Cmp(br) != 0 {
+				t.Errorf("Expected divMod256(%s, %s, %s) == %#x, %#x, got: %s, %s", hi, lo, d, bq, br, q, rem)
+			}
+		}
+	}
+}
+
+func TestModInverseUint128(t *testing.T) {
+	tests := []struct {
+		op1       Uint128
+		op2       Uint128
+		expected1 Uint128
+		expected2 bool
+	}{
+		{Uint128{hi: 0, lo: 3}, Uint128{hi: 0, lo: 7}, Uint128{hi: 0, lo: 5}, true},
+		{Uint128{hi: 0, lo: 2}, Uint128{hi: 0, lo: 4}, Uint128{}, false},
+		{Uint128{hi: 0, lo: 0}, Uint128{hi: 0, lo: 5}, Uint128{}, false},
+		{Uint128{hi: 0, lo: 5}, Uint128{hi: 0, lo: 1}, Uint128{hi: 0, lo: 0}, true},
+		{Uint128{hi: 0, lo: 2}, Uint128{hi: maxUint64, lo: maxUint64}, Uint128{hi: 1 << 63, lo: 0}, true},
+		{Uint128{hi: maxUint64, lo: maxUint64 - 1}, Uint128{hi: maxUint64, lo: maxUint64}, Uint128{hi: maxUint64, lo: maxUint64 - 1}, true},
+	}
+	for _, test := range tests {
+		result1, result2 := test.op1.ModInverse(test.op2)
+		if result2 != test.expected2 || result2 && !result1.Eq(test.expected1) {
+			t.Errorf("Expected %s.ModInverse(%s) == %s, %v got: %s, %v", test.op1, test.op2, test.expected1, test.expected2, result1, result2)
+		}
+	}
+}
+
+func TestModInverseRandUint128(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		x, m := randModUint128(r), randModUint128(r).Inc()
+		result, ok := x.ModInverse(m)
+		expected := new(big.Int).ModInverse(bigUint128(x), bigUint128(m))
+		if m.hi == 0 && m.lo == 1 {
+			// big.Int does not consider 0 to be invertible modulo 1
+			expected = new(big.Int)
+		}
+		switch {
+		case ok != (expected != nil):
+			t.Errorf("Expected %s.ModInverse(%s) to exist == %v, got: %v", x, m, expected != nil, ok)
+		case ok && bigUint128(result).Cmp(expected) != 0:
+			t.Errorf("Expected %s.ModInverse(%s) == %#x, got: %s", x, m, expected, result)
+		}
+	}
+}
+
+func TestModSqrtUint128(t *testing.T) {
+	primes := []Uint128{
+		{hi: 0, lo: 17},
+		{hi: 0, lo: 97},
+		{hi: 0, lo: maxUint64 - 58},          // 2^64 - 59
+		{hi: 1<<25 - 1, lo: maxUint64},       // 2^89 - 1
+		{hi: maxInt64, lo: maxUint64},        // 2^127 - 1
+		{hi: maxUint64, lo: maxUint64 - 158}, // 2^128 - 159
+		{hi: maxUint64, lo: maxUint64 - 172}, // 2^128 - 173
+	}
+	r := rand.New(rand.NewSource(1))
+	for _, p := range primes {
+		bp := bigUint128(p)
+		if !bp.ProbablyPrime(20) {
+			t.Fatalf("%s is not prime", p)
+		}
+		for i := 0; i < 50; i++ {
+			x := randModUint128(r).Mod(p)
+			result, ok := x.ModSqrt(p)
+			expected := new(big.Int).ModSqrt(bigUint128(x), bp)
+			switch {
+			case ok != (expected != nil):
+				t.Errorf("Expected %s.ModSqrt(%s) to exist == %v, got: %v", x, p, expected != nil, ok)
+			case ok && !result.MulMod(result, p).Eq(x):
+				t.Errorf("%s.ModSqrt(%s) returned incorrect result %s", x, p, result)
+			}
+		}
+	}
+}
+
+func TestModSqrtCompositeUint128(t *testing.T) {
+	// Composite moduli may pass Euler's criterion without having a quadratic non-residue, and must not hang
+	moduli := []Uint128{
+		{hi: 0, lo: 9},
+		{hi: 0, lo: 25},
+		{hi: 0, lo: 561},
+		{hi: 0, lo: 1 << 20},
+		{hi: 0, lo: 3 * 5 * 17 * 257 * 65537},
+	}
+	for _, m := range moduli {
+		for x := uint64(0); x < 64; x++ {
+			result, ok := Uint128FromUint64(x).ModSqrt(m)
+			if ok && !result.MulMod(result, m).Eq(Uint128FromUint64(x).Mod(m)) {
+				t.Errorf("%d.ModSqrt(%s) returned incorrect result %s", x, m, result)
+			}
+		}
+	}
+}
+
+func TestMulModUint128(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		x, y, m := randModUint128(r), randModUint128(r), randModUint128(r).Inc()
+		result := bigUint128(x.MulMod(y, m))
+		expected := new(big.Int).Mul(bigUint128(x), bigUint128(y))
+		expected.Mod(expected, bigUint128(m))
+		if result.Cmp(expected) != 0 {
+			t.Errorf("Expected %s.MulMod(%s, %s) == %#x, got: %#x", x, y, m, expected, result)
+		}
+	}
+}
+
+func TestPowModUint128(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		x, e, m := randModUint128(r), randModUint128(r), randModUint128(r).Inc()
+		result := bigUint128(x.PowMod(e, m))
+		expected := new(big.Int).Exp(bigUint128(x), bigUint128(e), bigUint128(m))
+		if result.Cmp(expected) != 0 {
+			t.Errorf("Expected %s.PowMod(%s, %s) == %#x, got: %#x", x, e, m, expected, result)
+		}
+	}
+}
+
+func TestSubModUint128(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		x, y, m := randModUint128(r), randModUint128(r), randModUint128(r).Inc()
+		result := bigUint128(x.SubMod(y, m))
+		expected := new(big.Int).Sub(bigUint128(x), bigUint128(y))
+		expected.Mod(expected, bigUint128(m))
+		if result.Cmp(expected) != 0 {
+			t.Errorf("Expected %s.SubMod(%s, %s) == %#x, got: %#x", x, y, m, expected, result)
+		}
+	}
+}
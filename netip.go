@@ -0,0 +1,76 @@
+package wide
+
+import (
+	"encoding/binary"
+	"net/netip"
+)
+
+// PrefixesFromRange returns the minimal list of CIDR prefixes which exactly covers the addresses from first to last, inclusive
+//
+// The prefixes are in ascending order. Prefixes which lie within the IPv4-mapped block ::ffff:0:0/96 are returned as IPv4
+// prefixes. The result is empty if first is greater than last.
+func PrefixesFromRange(first, last Uint128) []netip.Prefix {
+	var prefixes []netip.Prefix
+	for !first.Gt(last) {
+		// The largest aligned block which starts at first, and does not extend past last
+		k := first.TrailingZeros()
+		if n := last.Sub(first).Inc(); n.hi != 0 || n.lo != 0 {
+			k = min(k, n.Len()-1)
+		}
+		bits := int(int128Size - k)
+		addr := first.Addr()
+		if addr.Is4() {
+			bits -= int128Size - 32
+		}
+		prefixes = append(prefixes, netip.PrefixFrom(addr, bits))
+		next := first.Add(Uint128{lo: 1}.LShiftN(k))
+		if k == int128Size || next.Lt(first) {
+			break
+		}
+		first = next
+	}
+	return prefixes
+}
+
+// PrefixRange returns the first and last addresses of a prefix, as Uint128's
+//
+// IPv4 prefixes lie within the IPv4-mapped block ::ffff:0:0/96, as in Uint128FromAddr. Both are 0 if the prefix is invalid.
+func PrefixRange(p netip.Prefix) (first, last Uint128) {
+	if !p.IsValid() {
+		return Uint128{}, Uint128{}
+	}
+	first = Uint128FromAddr(p.Masked().Addr())
+	return first, first.Or(PrefixSize(p).Dec())
+}
+
+// PrefixSize returns the number of addresses in a prefix, or 0 if it is invalid
+//
+// This function overflows silently, i.e. the size of ::/0 is 0
+func PrefixSize(p netip.Prefix) Uint128 {
+	if !p.IsValid() {
+		return Uint128{}
+	}
+	return Uint128{lo: 1}.LShiftN(uint(p.Addr().BitLen() - p.Bits()))
+}
+
+// Uint128FromAddr returns an IP address as a Uint128, in network byte order
+//
+// IPv4 addresses are returned as IPv4-mapped IPv6 addresses (::ffff:a.b.c.d), and the zone of an IPv6 address is ignored.
+func Uint128FromAddr(a netip.Addr) Uint128 {
+	if !a.IsValid() {
+		return Uint128{}
+	}
+	b := a.As16()
+	return Uint128{hi: binary.BigEndian.Uint64(b[:8]), lo: binary.BigEndian.Uint64(b[8:])}
+}
+
+// Addr returns a Uint128 as an IP address
+//
+// Addresses within the IPv4-mapped block ::ffff:0:0/96 are returned as IPv4 addresses, so that Addr is the inverse of
+// Uint128FromAddr.
+func (x Uint128) Addr() netip.Addr {
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], x.hi)
+	binary.BigEndian.PutUint64(b[8:], x.lo)
+	return netip.AddrFrom16(b).Unmap()
+}
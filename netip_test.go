@@ -0,0 +1,123 @@
+package wide
+
+import (
+	"math/rand/v2"
+	"net/netip"
+	"reflect"
+	"testing"
+)
+
+func TestAddr(t *testing.T) {
+	tests := []struct {
+		addr     string
+		expected Uint128
+	}{
+		{"::", Uint128{}},
+		{"::1", Uint128{lo: 1}},
+		{"2001:db8::ff00:42:8329", Uint128{hi: 0x20010db800000000, lo: 0x0000ff0000428329}},
+		{"ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff", Uint128{hi: 1<<64 - 1, lo: 1<<64 - 1}},
+		{"192.0.2.1", Uint128{lo: 0xffffc0000201}},
+	}
+	for _, test := range tests {
+		a := netip.MustParseAddr(test.addr)
+		if result := Uint128FromAddr(a); result != test.expected {
+			t.Errorf("Expected Uint128FromAddr(%s) == %s, got: %s", a, test.expected, result)
+		}
+		if result := test.expected.Addr(); result != a {
+			t.Errorf("Expected %s.Addr() == %s, got: %s", test.expected, a, result)
+		}
+	}
+	if result, expected := Uint128FromAddr(netip.MustParseAddr("fe80::1%eth0")), (Uint128{hi: 0xfe80 << 48, lo: 1}); result != expected {
+		t.Errorf("Expected Uint128FromAddr(fe80::1%%eth0) == %s, got: %s", expected, result)
+	}
+	if result := Uint128FromAddr(netip.Addr{}); result != (Uint128{}) {
+		t.Errorf("Expected Uint128FromAddr(invalid) == 0x0, got: %s", result)
+	}
+}
+
+func TestPrefixRange(t *testing.T) {
+	tests := []struct {
+		prefix      string
+		first, last Uint128
+		size        Uint128
+	}{
+		{"::/0", Uint128{}, Uint128{hi: 1<<64 - 1, lo: 1<<64 - 1}, Uint128{}},
+		{"::1/128", Uint128{lo: 1}, Uint128{lo: 1}, Uint128{lo: 1}},
+		{"2001:db8::/32", Uint128{hi: 0x20010db8 << 32}, Uint128{hi: 0x20010db8<<32 | 1<<32 - 1, lo: 1<<64 - 1}, Uint128{hi: 1 << 32}},
+		{"2001:db8::1/64", Uint128{hi: 0x20010db8 << 32}, Uint128{hi: 0x20010db8 << 32, lo: 1<<64 - 1}, Uint128{hi: 1}},
+		{"0.0.0.0/0", Uint128{lo: 0xffff << 32}, Uint128{lo: 0xffffffffffff}, Uint128{lo: 1 << 32}},
+		{"192.0.2.77/24", Uint128{lo: 0xffffc0000200}, Uint128{lo: 0xffffc00002ff}, Uint128{lo: 256}},
+	}
+	for _, test := range tests {
+		p := netip.MustParsePrefix(test.prefix)
+		if first, last := PrefixRange(p); first != test.first || last != test.last {
+			t.Errorf("Expected PrefixRange(%s) == %s, %s, got: %s, %s", p, test.first, test.last, first, last)
+		}
+		if result := PrefixSize(p); result != test.size {
+			t.Errorf("Expected PrefixSize(%s) == %s, got: %s", p, test.size, result)
+		}
+	}
+	if first, last := PrefixRange(netip.Prefix{}); first != (Uint128{}) || last != (Uint128{}) {
+		t.Errorf("Expected PrefixRange(invalid) == 0x0, 0x0, got: %s, %s", first, last)
+	}
+}
+
+func TestPrefixesFromRange(t *testing.T) {
+	max := Uint128{hi: 1<<64 - 1, lo: 1<<64 - 1}
+	tests := []struct {
+		first, last Uint128
+		expected    []string
+	}{
+		{Uint128{lo: 1}, Uint128{}, nil},
+		{Uint128{}, max, []string{"::/0"}},
+		{max, max, []string{"ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff/128"}},
+		{Uint128{lo: 0xffff<<32 - 1}, Uint128{lo: 1 << 48}, []string{"::fffe:ffff:ffff/128", "0.0.0.0/0", "::1:0:0:0/128"}},
+		{Uint128FromAddr(netip.MustParseAddr("192.0.2.1")), Uint128FromAddr(netip.MustParseAddr("192.0.2.254")), []string{
+			"192.0.2.1/32", "192.0.2.2/31", "192.0.2.4/30", "192.0.2.8/29", "192.0.2.16/28", "192.0.2.32/27", "192.0.2.64/26",
+			"192.0.2.128/26", "192.0.2.192/27", "192.0.2.224/28", "192.0.2.240/29", "192.0.2.248/30", "192.0.2.252/31",
+			"192.0.2.254/32"}},
+		{Uint128FromAddr(netip.MustParseAddr("2001:db8::")), Uint128FromAddr(netip.MustParseAddr("2001:db8:0:2::ffff")), []string{
+			"2001:db8::/63", "2001:db8:0:2::/112"}},
+	}
+	for _, test := range tests {
+		var expected []netip.Prefix
+		for _, s := range test.expected {
+			expected = append(expected, netip.MustParsePrefix(s))
+		}
+		if result := PrefixesFromRange(test.first, test.last); !reflect.DeepEqual(result, expected) {
+			t.Errorf("Expected PrefixesFromRange(%s, %s) == %v, got: %v", test.first, test.last, expected, result)
+		}
+	}
+}
+
+func TestPrefixesFromRangeRandom(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for i := 0; i < 1000; i++ {
+		first, last := NewUint128(r.Uint64(), r.Uint64()), NewUint128(r.Uint64(), r.Uint64())
+		if i%2 == 0 {
+			// Short ranges, which split into more prefixes
+			last = first.Add(Uint128{lo: r.Uint64() >> r.UintN(64)})
+		}
+		if first.Gt(last) {
+			first, last = last, first
+		}
+		prefixes := PrefixesFromRange(first, last)
+		next := first
+		for j, p := range prefixes {
+			lo, hi := PrefixRange(p)
+			if p != p.Masked() || lo != next || hi.Gt(last) {
+				t.Fatalf("Expected PrefixesFromRange(%s, %s) to cover the range, got: %v", first, last, prefixes)
+			}
+			if j > 0 {
+				// Two adjacent prefixes of the same size which could be merged into one
+				if q := prefixes[j-1]; q.Bits() == p.Bits() && q.Addr().Is4() == p.Addr().Is4() && PrefixSize(q).LShift().Dec().And(Uint128FromAddr(q.Addr())) == (Uint128{}) {
+					t.Fatalf("Expected PrefixesFromRange(%s, %s) to be minimal, got: %v", first, last, prefixes)
+				}
+			}
+			next = hi.Inc()
+		}
+		if next != last.Inc() {
+			t.Fatalf("Expected PrefixesFromRange(%s, %s) to cover the range, got: %v", first, last, prefixes)
+		}
+	}
+}
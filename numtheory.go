@@ -0,0 +1,149 @@
+package wide
+
+// ExtendedGCD returns the greatest common divisor g of x and y, along with Bézout coefficients a and b such that x*a + y*b = g
+//
+// The coefficients are minimal, i.e. |a| <= y/(2g) and |b| <= x/(2g), apart from when x or y is 0, or x == y.
+func (x Uint128) ExtendedGCD(y Uint128) (g Uint128, a, b Int128) {
+	// The coefficients alternate in sign from one step to the next, so only their magnitudes are tracked
+	r0, r1 := x, y
+	s0, s1 := Uint128{lo: 1}, Uint128{}
+	t0, t1 := Uint128{}, Uint128{lo: 1}
+	odd := false
+	for r1.hi != 0 || r1.lo != 0 {
+		q, r := r0.DivMod(r1)
+		r0, r1 = r1, r
+		s0, s1 = s1, s0.Add(q.Mul(s1))
+		t0, t1 = t1, t0.Add(q.Mul(t1))
+		odd = !odd
+	}
+	a, b = s0.Int128(), t0.Int128()
+	if odd {
+		return r0, a.Neg(), b
+	}
+	return r0, a, b.Neg()
+}
+
+// GCD returns the greatest common divisor of two Uint128's
+//
+// GCD uses the binary GCD algorithm (also known as Stein's algorithm). The edge case GCD(0, 0) returns 0.
+func (x Uint128) GCD(y Uint128) Uint128 {
+	switch {
+	case x.hi == 0 && x.lo == 0:
+		return y
+	case y.hi == 0 && y.lo == 0:
+		return x
+	}
+	k := x.Or(y).TrailingZeros()
+	x = x.RShiftN(x.TrailingZeros())
+	for {
+		y = y.RShiftN(y.TrailingZeros())
+		if x.Gt(y) {
+			x, y = y, x
+		}
+		y = y.Sub(x)
+		if y.hi == 0 && y.lo == 0 {
+			return x.LShiftN(k)
+		}
+	}
+}
+
+// Jacobi returns the Jacobi symbol (x/n), either +1, -1, or 0
+//
+// Jacobi panics if n is even.
+func (x Uint128) Jacobi(n Uint128) int {
+	if n.lo&1 == 0 {
+		panic("jacobi symbol of even modulus")
+	}
+	j := 1
+	for a := x.Mod(n); a.hi != 0 || a.lo != 0; {
+		// Factors of two contribute -1 when n = 3 or 5 (mod 8)
+		s := a.TrailingZeros()
+		a = a.RShiftN(s)
+		if nmod8 := n.lo & 7; s&1 != 0 && (nmod8 == 3 || nmod8 == 5) {
+			j = -j
+		}
+		// Quadratic reciprocity
+		if a.lo&3 == 3 && n.lo&3 == 3 {
+			j = -j
+		}
+		a, n = n.Mod(a), a
+	}
+	if n.hi != 0 || n.lo != 1 {
+		return 0
+	}
+	return j
+}
+
+// LCM returns the least common multiple of two Uint128's, and whether the result was computed without overflowing
+//
+// The edge case LCM(0, y) returns 0.
+func (x Uint128) LCM(y Uint128) (z Uint128, ok bool) {
+	if x.hi == 0 && x.lo == 0 || y.hi == 0 && y.lo == 0 {
+		return z, true
+	}
+	hi, z := x.Div(x.GCD(y)).mulFull(y)
+	return z, hi.hi == 0 && hi.lo == 0
+}
+
+// Legendre returns the Legendre symbol (x/p), either +1, -1, or 0
+//
+// The modulus p must be an odd prime, otherwise the result is undefined. Legendre panics if p is even.
+func (x Uint128) Legendre(p Uint128) int {
+	// For an odd prime p, the Jacobi symbol coincides with the Legendre symbol
+	return x.Jacobi(p)
+}
+
+// ExtendedGCD returns the greatest common divisor g of |x| and |y|, along with Bézout coefficients a and b such that x*a + y*b = g
+//
+// This function overflows silently when g or a coefficient is 2^127, which can only occur when x or y is the minimum Int128.
+func (x Int128) ExtendedGCD(y Int128) (g, a, b Int128) {
+	ug, a, b := x.Abs().Uint128().ExtendedGCD(y.Abs().Uint128())
+	if x.hi < 0 {
+		a = a.Neg()
+	}
+	if y.hi < 0 {
+		b = b.Neg()
+	}
+	return ug.Int128(), a, b
+}
+
+// GCD returns the greatest common divisor of |x| and |y|
+//
+// This function overflows silently when the result is 2^127, which can only occur when x or y is the minimum Int128.
+func (x Int128) GCD(y Int128) Int128 {
+	return x.Abs().Uint128().GCD(y.Abs().Uint128()).Int128()
+}
+
+// Jacobi returns the Jacobi symbol (x/n), either +1, -1, or 0
+//
+// Jacobi panics if n is even. For negative n, the sign convention matches big.Jacobi.
+func (x Int128) Jacobi(n Int128) int {
+	if n.lo&1 == 0 {
+		panic("jacobi symbol of even modulus")
+	}
+	j := 1
+	if n.hi < 0 && x.hi < 0 {
+		j = -1
+	}
+	un := n.Abs().Uint128()
+	a := x.Abs().Uint128().Mod(un)
+	if x.hi < 0 && (a.hi != 0 || a.lo != 0) {
+		a = un.Sub(a)
+	}
+	return j * a.Jacobi(un)
+}
+
+// LCM returns the least common multiple of |x| and |y|, and whether the result was computed without overflowing
+//
+// The edge case LCM(0, y) returns 0.
+func (x Int128) LCM(y Int128) (z Int128, ok bool) {
+	uz, ok := x.Abs().Uint128().LCM(y.Abs().Uint128())
+	return uz.Int128(), ok && uz.hi <= maxInt64
+}
+
+// Legendre returns the Legendre symbol (x/p), either +1, -1, or 0
+//
+// The modulus p must be an odd prime, otherwise the result is undefined. Legendre panics if p is even.
+func (x Int128) Legendre(p Int128) int {
+	return x.Jacobi(p)
+}
@@ -0,0 +1,213 @@
+package wide
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+// bigInt128 returns a big.Int representation of an Int128
+func bigInt128(x Int128) *big.Int {
+	if x.hi < 0 {
+		return new(big.Int).Neg(bigUint128(x.Neg().Uint128()))
+	}
+	return bigUint128(x.Uint128())
+}
+
+// randNumTheoryInt128 returns a random Int128 with a random bit length and sign
+func randNumTheoryInt128(r *rand.Rand) Int128 {
+	x := randModUint128(r).RShift().Int128()
+	if r.Intn(2) == 0 {
+		return x.Neg()
+	}
+	return x
+}
+
+func TestExtendedGCDUint128(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		x, y := randModUint128(r), randModUint128(r)
+		if i%10 == 0 {
+			// Exercise inputs with a large common factor
+			f := randModUint128(r).RShiftN(64)
+			x, y = x.RShiftN(64).Mul(f), y.RShiftN(64).Mul(f)
+		}
+		g, a, b := x.ExtendedGCD(y)
+		expected := new(big.Int).GCD(nil, nil, bigUint128(x), bigUint128(y))
+		if bigUint128(g).Cmp(expected) != 0 {
+			t.Errorf("Expected %s.ExtendedGCD(%s) == %#x, got: %s", x, y, expected, g)
+		}
+		sum := new(big.Int).Mul(bigUint128(x), bigInt128(a))
+		sum.Add(sum, new(big.Int).Mul(bigUint128(y), bigInt128(b)))
+		if sum.Cmp(expected) != 0 {
+			t.Errorf("%s.ExtendedGCD(%s) returned invalid coefficients %s, %s", x, y, a, b)
+		}
+	}
+}
+
+func TestExtendedGCDInt128(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		x, y := randNumTheoryInt128(r), randNumTheoryInt128(r)
+		g, a, b := x.ExtendedGCD(y)
+		expected := new(big.Int).GCD(nil, nil, bigInt128(x), bigInt128(y))
+		if bigInt128(g).Cmp(expected) != 0 {
+			t.Errorf("Expected %s.ExtendedGCD(%s) == %#x, got: %s", x, y, expected, g)
+		}
+		sum := new(big.Int).Mul(bigInt128(x), bigInt128(a))
+		sum.Add(sum, new(big.Int).Mul(bigInt128(y), bigInt128(b)))
+		if sum.Cmp(expected) != 0 {
+			t.Errorf("%s.ExtendedGCD(%s) returned invalid coefficients %s, %s", x, y, a, b)
+		}
+	}
+}
+
+func TestGCDUint128(t *testing.T) {
+	tests := []struct {
+		op1      Uint128
+		op2      Uint128
+		expected Uint128
+	}{
+		{Uint128{hi: 0, lo: 0}, Uint128{hi: 0, lo: 0}, Uint128{hi: 0, lo: 0}},
+		{Uint128{hi: 0, lo: 0}, Uint128{hi: 0, lo: 5}, Uint128{hi: 0, lo: 5}},
+		{Uint128{hi: 0, lo: 5}, Uint128{hi: 0, lo: 0}, Uint128{hi: 0, lo: 5}},
+		{Uint128{hi: 0, lo: 12}, Uint128{hi: 0, lo: 18}, Uint128{hi: 0, lo: 6}},
+		{Uint128{hi: 1, lo: 0}, Uint128{hi: 0, lo: 1 << 63}, Uint128{hi: 0, lo: 1 << 63}},
+		{Uint128{hi: maxUint64, lo: maxUint64}, Uint128{hi: 0, lo: maxUint64}, Uint128{hi: 0, lo: maxUint64}},
+		{Uint128{hi: maxUint64, lo: maxUint64}, Uint128{hi: maxUint64, lo: maxUint64 - 1}, Uint128{hi: 0, lo: 1}},
+	}
+	for _, test := range tests {
+		result := test.op1.GCD(test.op2)
+		if !result.Eq(test.expected) {
+			t.Errorf("Expected %s.GCD(%s) == %s, got: %s", test.op1, test.op2, test.expected, result)
+		}
+	}
+}
+
+func TestGCDRandUint128(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		x, y := randModUint128(r), randModUint128(r)
+		if i%10 == 0 {
+			f := randModUint128(r).RShiftN(64)
+			x, y = x.RShiftN(64).Mul(f), y.RShiftN(64).Mul(f)
+		}
+		result := bigUint128(x.GCD(y))
+		expected := new(big.Int).GCD(nil, nil, bigUint128(x), bigUint128(y))
+		if result.Cmp(expected) != 0 {
+			t.Errorf("Expected %s.GCD(%s) == %#x, got: %#x", x, y, expected, result)
+		}
+	}
+}
+
+func TestGCDInt128(t *testing.T) {
+	tests := []struct {
+		op1      Int128
+		op2      Int128
+		expected Int128
+	}{
+		{Int128FromInt64(-12), Int128FromInt64(18), Int128FromInt64(6)},
+		{Int128FromInt64(12), Int128FromInt64(-18), Int128FromInt64(6)},
+		{Int128FromInt64(-12), Int128FromInt64(-18), Int128FromInt64(6)},
+		{Int128FromInt64(0), Int128FromInt64(-7), Int128FromInt64(7)},
+	}
+	for _, test := range tests {
+		result := test.op1.GCD(test.op2)
+		if !result.Eq(test.expected) {
+			t.Errorf("Expected %s.GCD(%s) == %s, got: %s", test.op1, test.op2, test.expected, result)
+		}
+	}
+}
+
+func TestJacobiUint128(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		x, n := randModUint128(r), randModUint128(r).Or(Uint128{lo: 1})
+		result := x.Jacobi(n)
+		expected := big.Jacobi(bigUint128(x), bigUint128(n))
+		if result != expected {
+			t.Errorf("Expected %s.Jacobi(%s) == %v, got: %v", x, n, expected, result)
+		}
+	}
+}
+
+func TestJacobiInt128(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		x, n := randNumTheoryInt128(r), randNumTheoryInt128(r).Or(Int128{lo: 1})
+		result := x.Jacobi(n)
+		expected := big.Jacobi(bigInt128(x), bigInt128(n))
+		if result != expected {
+			t.Errorf("Expected %s.Jacobi(%s) == %v, got: %v", x, n, expected, result)
+		}
+	}
+}
+
+func TestJacobiEvenUint128(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Jacobi symbol of an even modulus did not panic")
+		}
+	}()
+	Uint128{hi: 0, lo: 3}.Jacobi(Uint128{hi: 0, lo: 4})
+}
+
+func TestLCMUint128(t *testing.T) {
+	tests := []struct {
+		op1       Uint128
+		op2       Uint128
+		expected1 Uint128
+		expected2 bool
+	}{
+		{Uint128{hi: 0, lo: 0}, Uint128{hi: 0, lo: 5}, Uint128{hi: 0, lo: 0}, true},
+		{Uint128{hi: 0, lo: 4}, Uint128{hi: 0, lo: 6}, Uint128{hi: 0, lo: 12}, true},
+		{Uint128{hi: 0, lo: maxUint64}, Uint128{hi: 0, lo: maxUint64 - 1}, Uint128{hi: maxUint64 - 2, lo: 2}, true},
+		{Uint128{hi: 1, lo: 0}, Uint128{hi: 0, lo: 3}, Uint128{hi: 3, lo: 0}, true},
+		{Uint128{hi: 1 << 63, lo: 0}, Uint128{hi: 0, lo: 3}, Uint128{}, false},
+		{Uint128{hi: 1 << 63, lo: 0}, Uint128{hi: 0, lo: 4}, Uint128{hi: 1 << 63, lo: 0}, true},
+	}
+	for _, test := range tests {
+		result1, result2 := test.op1.LCM(test.op2)
+		if result2 != test.expected2 || result2 && !result1.Eq(test.expected1) {
+			t.Errorf("Expected %s.LCM(%s) == %s, %v got: %s, %v", test.op1, test.op2, test.expected1, test.expected2, result1, result2)
+		}
+	}
+}
+
+func TestLCMInt128(t *testing.T) {
+	tests := []struct {
+		op1       Int128
+		op2       Int128
+		expected1 Int128
+		expected2 bool
+	}{
+		{Int128FromInt64(-4), Int128FromInt64(6), Int128FromInt64(12), true},
+		{Int128{hi: 1 << 62, lo: 0}, Int128FromInt64(-2), Int128{hi: 1 << 62, lo: 0}, true},
+		{Int128{hi: 1 << 62, lo: 0}, Int128FromInt64(-3), Int128{}, false},
+	}
+	for _, test := range tests {
+		result1, result2 := test.op1.LCM(test.op2)
+		if result2 != test.expected2 || result2 && !result1.Eq(test.expected1) {
+			t.Errorf("Expected %s.LCM(%s) == %s, %v got: %s, %v", test.op1, test.op2, test.expected1, test.expected2, result1, result2)
+		}
+	}
+}
+
+func TestLegendreUint128(t *testing.T) {
+	p := Uint128{hi: maxUint64, lo: maxUint64 - 158} // 2^128 - 159
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		x := randModUint128(r)
+		// Euler's criterion
+		expected := 1
+		switch e := x.PowMod(p.RShift(), p); {
+		case e.hi == 0 && e.lo == 0:
+			expected = 0
+		case !e.Eq(Uint128{lo: 1}):
+			expected = -1
+		}
+		if result := x.Legendre(p); result != expected {
+			t.Errorf("Expected %s.Legendre(%s) == %v, got: %v", x, p, expected, result)
+		}
+	}
+}
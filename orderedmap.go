@@ -0,0 +1,99 @@
+package wide
+
+import "iter"
+
+// OrderedMap128 is an ordered map from Uint128 keys to values of type V, implemented as a B-tree
+//
+// Unlike a Go map, it can be iterated in key order, and scanned by key range. The zero value is an empty map. An
+// OrderedMap128 must not be modified while it is being iterated.
+type OrderedMap128[V any] struct {
+	t btree[Uint128, V]
+}
+
+// OrderedMapInt128 is an ordered map from Int128 keys to values of type V, implemented as a B-tree
+//
+// Unlike a Go map, it can be iterated in key order, and scanned by key range. The zero value is an empty map. An
+// OrderedMapInt128 must not be modified while it is being iterated.
+type OrderedMapInt128[V any] struct {
+	t btree[Int128, V]
+}
+
+// All returns an iterator over the entries of an OrderedMap128, in ascending key order
+func (m *OrderedMap128[V]) All() iter.Seq2[Uint128, V] {
+	return m.t.all(nil, nil)
+}
+
+// Ceiling returns the entry of an OrderedMap128 with the least key which is greater than or equal to k, if any
+func (m *OrderedMap128[V]) Ceiling(k Uint128) (key Uint128, v V, ok bool) {
+	return m.t.ceiling(k)
+}
+
+// Delete removes the entry with key k from an OrderedMap128, and returns its value, if any
+func (m *OrderedMap128[V]) Delete(k Uint128) (v V, ok bool) {
+	return m.t.delete(k)
+}
+
+// Floor returns the entry of an OrderedMap128 with the greatest key which is less than or equal to k, if any
+func (m *OrderedMap128[V]) Floor(k Uint128) (key Uint128, v V, ok bool) {
+	return m.t.floor(k)
+}
+
+// Get returns the value of the entry with key k in an OrderedMap128, if any
+func (m *OrderedMap128[V]) Get(k Uint128) (v V, ok bool) {
+	return m.t.get(k)
+}
+
+// Len returns the number of entries in an OrderedMap128
+func (m *OrderedMap128[V]) Len() int {
+	return m.t.length
+}
+
+// Put sets the value of the entry with key k in an OrderedMap128, inserting it if it does not exist
+func (m *OrderedMap128[V]) Put(k Uint128, v V) {
+	m.t.put(k, v)
+}
+
+// Range returns an iterator over the entries of an OrderedMap128 with keys in [lo, hi], in ascending key order
+func (m *OrderedMap128[V]) Range(lo, hi Uint128) iter.Seq2[Uint128, V] {
+	return m.t.all(&lo, &hi)
+}
+
+// All returns an iterator over the entries of an OrderedMapInt128, in ascending key order
+func (m *OrderedMapInt128[V]) All() iter.Seq2[Int128, V] {
+	return m.t.all(nil, nil)
+}
+
+// Ceiling returns the entry of an OrderedMapInt128 with the least key which is greater than or equal to k, if any
+func (m *OrderedMapInt128[V]) Ceiling(k Int128) (key Int128, v V, ok bool) {
+	return m.t.ceiling(k)
+}
+
+// Delete removes the entry with key k from an OrderedMapInt128, and returns its value, if any
+func (m *OrderedMapInt128[V]) Delete(k Int128) (v V, ok bool) {
+	return m.t.delete(k)
+}
+
+// Floor returns the entry of an OrderedMapInt128 with the greatest key which is less than or equal to k, if any
+func (m *OrderedMapInt128[V]) Floor(k Int128) (key Int128, v V, ok bool) {
+	return m.t.floor(k)
+}
+
+// Get returns the value of the entry with key k in an OrderedMapInt128, if any
+func (m *OrderedMapInt128[V]) Get(k Int128) (v V, ok bool) {
+	return m.t.get(k)
+}
+
+// Len returns the number of entries in an OrderedMapInt128
+func (m *OrderedMapInt128[V]) Len() int {
+	return m.t.length
+}
+
+// Put sets the value of the entry with key k in an OrderedMapInt128, inserting it if it does not exist
+func (m *OrderedMapInt128[V]) Put(k Int128, v V) {
+	m.t.put(k, v)
+}
+
+// Range returns an iterator over the entries of an OrderedMapInt128 with keys in [lo, hi], in ascending key order
+func (m *OrderedMapInt128[V]) Range(lo, hi Int128) iter.Seq2[Int128, V] {
+	return m.t.all(&lo, &hi)
+}
@@ -0,0 +1,162 @@
+package wide
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// checkBtree checks the invariants of a B-tree node: its key counts, key order, and that all of its leaves are at the same depth
+func checkBtree[K btreeKey[K], V any](t *testing.T, n *btreeNode[K, V], root bool) (depth int) {
+	t.Helper()
+	if len(n.keys) > 2*btreeDegree-1 || !root && len(n.keys) < btreeDegree-1 || len(n.vals) != len(n.keys) {
+		t.Fatalf("Expected a B-tree node with %d to %d keys, got: %d", btreeDegree-1, 2*btreeDegree-1, len(n.keys))
+	}
+	for i := 1; i < len(n.keys); i++ {
+		if n.keys[i-1].Cmp(n.keys[i]) >= 0 {
+			t.Fatalf("Expected the keys of a B-tree node to be sorted")
+		}
+	}
+	if n.leaf() {
+		return 0
+	}
+	if len(n.children) != len(n.keys)+1 {
+		t.Fatalf("Expected a B-tree node with %d keys to have %d children, got: %d", len(n.keys), len(n.keys)+1, len(n.children))
+	}
+	for i, c := range n.children {
+		if d := checkBtree(t, c, false); i == 0 {
+			depth = d
+		} else if d != depth {
+			t.Fatalf("Expected all leaves of a B-tree at the same depth")
+		}
+	}
+	return depth + 1
+}
+
+func TestOrderedMap128(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	var m OrderedMap128[int]
+	model := make(map[Uint128]int)
+	key := func() Uint128 { return Uint128{hi: r.Uint64N(2), lo: r.Uint64N(3000)} }
+	for i := 0; i < 20000; i++ {
+		k := key()
+		switch op := r.IntN(5); {
+		case op < 3 && i < 15000:
+			m.Put(k, i)
+			model[k] = i
+		default:
+			v, ok := m.Delete(k)
+			if ev, eok := model[k]; v != ev || ok != eok {
+				t.Fatalf("Expected OrderedMap128.Delete(%s) == %d, %t, got: %d, %t", k, ev, eok, v, ok)
+			}
+			delete(model, k)
+		}
+		if i%1000 == 0 && m.t.root != nil {
+			checkBtree(t, m.t.root, true)
+		}
+	}
+	if m.Len() != len(model) {
+		t.Fatalf("Expected OrderedMap128.Len() == %d, got: %d", len(model), m.Len())
+	}
+	keys := make([]Uint128, 0, len(model))
+	for k := range model {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, Uint128.Cmp)
+	i := 0
+	for k, v := range m.All() {
+		if k != keys[i] || v != model[k] {
+			t.Fatalf("Expected OrderedMap128.All() entry %d == %s, %d, got: %s, %d", i, keys[i], model[keys[i]], k, v)
+		}
+		i++
+	}
+	for j := 0; j < 1000; j++ {
+		k := key()
+		if v, ok := m.Get(k); v != model[k] || ok != slices.Contains(keys, k) {
+			t.Fatalf("Expected OrderedMap128.Get(%s) == %d, got: %d, %t", k, model[k], v, ok)
+		}
+		n, found := slices.BinarySearchFunc(keys, k, Uint128.Cmp)
+		fk, fv, fok := m.Floor(k)
+		switch {
+		case found && (fk != k || fv != model[k] || !fok):
+			t.Fatalf("Expected OrderedMap128.Floor(%s) == %s, got: %s, %t", k, k, fk, fok)
+		case !found && n > 0 && (fk != keys[n-1] || !fok):
+			t.Fatalf("Expected OrderedMap128.Floor(%s) == %s, got: %s, %t", k, keys[n-1], fk, fok)
+		case !found && n == 0 && fok:
+			t.Fatalf("Expected OrderedMap128.Floor(%s) to be absent, got: %s", k, fk)
+		}
+		ck, _, cok := m.Ceiling(k)
+		if n < len(keys) && (ck != keys[n] || !cok) || n == len(keys) && cok {
+			t.Fatalf("Expected OrderedMap128.Ceiling(%s) at index %d, got: %s, %t", k, n, ck, cok)
+		}
+		hi := k.Add(Uint128{lo: r.Uint64N(100)})
+		end, _ := slices.BinarySearchFunc(keys, hi.Inc(), Uint128.Cmp)
+		var got []Uint128
+		for rk := range m.Range(k, hi) {
+			got = append(got, rk)
+		}
+		if !slices.Equal(got, keys[n:end]) {
+			t.Fatalf("Expected OrderedMap128.Range(%s, %s) == %v, got: %v", k, hi, keys[n:end], got)
+		}
+	}
+	for k := range model {
+		m.Delete(k)
+	}
+	if m.Len() != 0 || m.t.root != nil {
+		t.Errorf("Expected an empty OrderedMap128 after deleting every key")
+	}
+}
+
+func TestOrderedMapInt128(t *testing.T) {
+	var m OrderedMapInt128[int64]
+	for i := int64(-500); i < 500; i += 2 {
+		m.Put(Int128FromInt64(i), i)
+	}
+	if k, v, ok := m.Floor(Int128FromInt64(-1)); !ok || k != Int128FromInt64(-2) || v != -2 {
+		t.Errorf("Expected OrderedMapInt128.Floor(-1) == -2, got: %s, %d, %t", k, v, ok)
+	}
+	if k, _, ok := m.Ceiling(Int128FromInt64(-499)); !ok || k != Int128FromInt64(-498) {
+		t.Errorf("Expected OrderedMapInt128.Ceiling(-499) == -498, got: %s, %t", k, ok)
+	}
+	if _, _, ok := m.Ceiling(Int128FromInt64(499)); ok {
+		t.Errorf("Expected OrderedMapInt128.Ceiling(499) to be absent")
+	}
+	var got []int64
+	for _, v := range m.Range(Int128FromInt64(-3), Int128FromInt64(3)) {
+		got = append(got, v)
+	}
+	if expected := []int64{-2, 0, 2}; !slices.Equal(got, expected) {
+		t.Errorf("Expected OrderedMapInt128.Range(-3, 3) == %v, got: %v", expected, got)
+	}
+	n := 0
+	for range m.All() {
+		if n++; n == 10 {
+			break
+		}
+	}
+	if v, ok := m.Delete(Int128FromInt64(0)); !ok || v != 0 || m.Len() != 499 {
+		t.Errorf("Expected OrderedMapInt128.Delete(0) == 0, got: %d, %t", v, ok)
+	}
+}
+
+func BenchmarkOrderedMap128Get(b *testing.B) {
+	r := rand.New(rand.NewPCG(1, 2))
+	keys := make([]Uint128, 1<<16)
+	var m OrderedMap128[int]
+	gm := make(map[Uint128]int)
+	for i := range keys {
+		keys[i] = NewUint128(r.Uint64(), r.Uint64())
+		m.Put(keys[i], i)
+		gm[keys[i]] = i
+	}
+	b.Run("btree", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			m.Get(keys[i&(len(keys)-1)])
+		}
+	})
+	b.Run("map", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = gm[keys[i&(len(keys)-1)]]
+		}
+	})
+}
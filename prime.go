@@ -0,0 +1,243 @@
+package wide
+
+import "sort"
+
+// millerRabinBound is the smallest strong pseudoprime to the first 13 prime bases (Sorenson and Webster, 2015)
+var millerRabinBound = Uint128{hi: 0x2be69, lo: 0x51adc5b22410a5fd}
+
+// Factorize returns the prime factorization of x in ascending order, with repeated factors listed repeatedly
+//
+// Small factors are found by trial division, and the rest are split with Pollard's rho algorithm using Brent's cycle detection.
+// The edge cases 0 and 1 return no factors.
+func (x Uint128) Factorize() (factors []Uint128) {
+	if x.hi == 0 && x.lo <= 1 {
+		return nil
+	}
+	for _, p := range smallPrimes {
+		for x.modSmall(p) == 0 {
+			factors = append(factors, Uint128{lo: p})
+			x = x.Div(Uint128{lo: p})
+		}
+	}
+	if x.hi != 0 || x.lo != 1 {
+		factors = x.factorizeRho(factors)
+	}
+	sort.Slice(factors, func(i, j int) bool {
+		return factors[i].Lt(factors[j])
+	})
+	return factors
+}
+
+// factorizeRho appends the prime factors of an odd x > 1 which has no small factors
+func (x Uint128) factorizeRho(factors []Uint128) []Uint128 {
+	if x.IsPrime() {
+		return append(factors, x)
+	}
+	c := NewMontgomery128(x)
+	var d Uint128
+	for k := uint64(1); ; k++ {
+		if d = c.pollardBrent(Uint128{lo: k}); !d.Eq(x) {
+			break
+		}
+	}
+	factors = d.factorizeRho(factors)
+	return x.Div(d).factorizeRho(factors)
+}
+
+// IsPrime returns whether x is prime
+//
+// The result is exact for x below 3317044064679887385961981 (roughly 2^81), where Miller-Rabin with the first 13 prime bases is
+// deterministic. Above that, the Baillie-PSW test is used, for which no counterexamples are known.
+func (x Uint128) IsPrime() bool {
+	if x.hi == 0 && x.lo < 2 {
+		return false
+	}
+	for _, p := range smallPrimes {
+		if x.hi == 0 && x.lo == p {
+			return true
+		}
+		if x.modSmall(p) == 0 {
+			return false
+		}
+	}
+	c := NewMontgomery128(x)
+	if x.Lt(millerRabinBound) {
+		for _, p := range smallPrimes[:13] {
+			if !c.millerRabin(Uint128{lo: p}) {
+				return false
+			}
+		}
+		return true
+	}
+	return c.millerRabin(Uint128{lo: 2}) && c.strongLucas()
+}
+
+// modSmall returns x modulo a small p, which must be less than 2^32
+func (x Uint128) modSmall(p uint64) uint64 {
+	// 2^64 mod p, computed as (2^64 - p) mod p
+	r64 := -p % p
+	return (x.hi%p*r64 + x.lo%p) % p
+}
+
+// NextPrime returns the smallest prime greater than x, and whether such a prime is representable as a Uint128
+func (x Uint128) NextPrime() (p Uint128, ok bool) {
+	if x.hi == 0 && x.lo < 2 {
+		return Uint128{lo: 2}, true
+	}
+	// Only odd candidates need to be considered
+	p = x.Inc().Or(Uint128{lo: 1})
+	for p.Gt(x) {
+		if p.IsPrime() {
+			return p, true
+		}
+		p = p.Add(Uint128{lo: 2})
+	}
+	return Uint128{}, false
+}
+
+// add returns x + y modulo the modulus, where x and y are already reduced
+func (c *Montgomery128) add(x, y Uint128) Uint128 {
+	z := x.Add(y)
+	if z.Lt(x) || z.Gte(c.m) {
+		z = z.Sub(c.m)
+	}
+	return z
+}
+
+// half returns x/2 modulo the (odd) modulus
+func (c *Montgomery128) half(x Uint128) Uint128 {
+	if x.lo&1 == 0 {
+		return x.RShift()
+	}
+	// (x + m) / 2, without overflowing
+	return x.RShift().Add(c.m.RShift()).Inc()
+}
+
+// millerRabin returns whether the modulus is a strong probable prime to base a
+func (c *Montgomery128) millerRabin(a Uint128) bool {
+	one := c.r
+	minusOne := c.m.Sub(one)
+	d := c.m.Dec()
+	s := d.TrailingZeros()
+	d = d.RShiftN(s)
+	y := c.Pow(c.ToMont(a), d)
+	if y.Eq(one) || y.Eq(minusOne) {
+		return true
+	}
+	for i := uint(1); i < s; i++ {
+		y = c.Mul(y, y)
+		if y.Eq(minusOne) {
+			return true
+		}
+	}
+	return false
+}
+
+// pollardBrent returns a non-trivial factor of the modulus, or the modulus itself if the pseudo-random sequence y^2 + k failed to find one
+func (c *Montgomery128) pollardBrent(k Uint128) Uint128 {
+	const batch = 128
+	n := c.m
+	k = c.ToMont(k)
+	f := func(y Uint128) Uint128 {
+		return c.add(c.Mul(y, y), k)
+	}
+	one := Uint128{lo: 1}
+	y, q, g := c.ToMont(Uint128{lo: 2}), c.r, one
+	var x, ys Uint128
+	for r := 1; g.Eq(one); r <<= 1 {
+		x = y
+		for i := 0; i < r; i++ {
+			y = f(y)
+		}
+		for j := 0; j < r && g.Eq(one); j += batch {
+			ys = y
+			for i := 0; i < batch && i < r-j; i++ {
+				y = f(y)
+				q = c.Mul(q, c.sub(x, y))
+			}
+			g = q.GCD(n)
+		}
+	}
+	if g.Eq(n) {
+		// The batch overshot, so backtrack one step at a time
+		for g = one; g.Eq(one); {
+			ys = f(ys)
+			g = c.sub(x, ys).GCD(n)
+		}
+	}
+	return g
+}
+
+// strongLucas returns whether the modulus is a strong Lucas probable prime, with parameters chosen by Selfridge's method
+func (c *Montgomery128) strongLucas() bool {
+	n := c.m
+	if sq := n.Sqrt(); sq.Mul(sq).Eq(n) {
+		// There is no D for which the Jacobi symbol is -1
+		return false
+	}
+	// Find the first D in 5, -7, 9, -11, ... such that (D/n) = -1
+	var d int64 = 5
+	var dm Uint128
+	for {
+		dm = Uint128{lo: uint64(d)}
+		if d < 0 {
+			dm = n.Sub(Uint128{lo: uint64(-d)})
+		}
+		j := dm.Jacobi(n)
+		if j == -1 {
+			break
+		}
+		if j == 0 {
+			// n shares a factor with |D|, which is smaller than n
+			return false
+		}
+		if d > 0 {
+			d = -d - 2
+		} else {
+			d = -d + 2
+		}
+	}
+	// P = 1 and Q = (1 - D) / 4
+	q := (1 - d) / 4
+	qm := Uint128{lo: uint64(q)}
+	if q < 0 {
+		qm = n.Sub(Uint128{lo: uint64(-q)})
+	}
+	dm, qm = c.ToMont(dm), c.ToMont(qm)
+	// n+1 = k*2^s, with k odd
+	k := n.Inc()
+	s := k.TrailingZeros()
+	k = k.RShiftN(s)
+	// Compute U_k, V_k and Q^k, starting from U_1 = 1, V_1 = P = 1, and Q^1 = Q
+	u, v, qk := c.r, c.r, qm
+	for i := k.Len() - 1; i > 0; i-- {
+		u = c.Mul(u, v)
+		v = c.sub(c.Mul(v, v), c.add(qk, qk))
+		qk = c.Mul(qk, qk)
+		if k.RShiftN(i-1).lo&1 != 0 {
+			u, v = c.half(c.add(u, v)), c.half(c.add(c.Mul(dm, u), v))
+			qk = c.Mul(qk, qm)
+		}
+	}
+	zero := Uint128{}
+	if u.Eq(zero) || v.Eq(zero) {
+		return true
+	}
+	for i := uint(1); i < s; i++ {
+		v = c.sub(c.Mul(v, v), c.add(qk, qk))
+		if v.Eq(zero) {
+			return true
+		}
+		qk = c.Mul(qk, qk)
+	}
+	return false
+}
+
+// sub returns x - y modulo the modulus, where x and y are already reduced
+func (c *Montgomery128) sub(x, y Uint128) Uint128 {
+	z := x.Sub(y)
+	if x.Lt(y) {
+		z = z.Add(c.m)
+	}
+	return z
+}
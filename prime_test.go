@@ -0,0 +1,127 @@
+package wide
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestIsPrimeUint128(t *testing.T) {
+	tests := []struct {
+		inp      Uint128
+		expected bool
+	}{
+		{Uint128{hi: 0, lo: 0}, false},
+		{Uint128{hi: 0, lo: 1}, false},
+		{Uint128{hi: 0, lo: 2}, true},
+		{Uint128{hi: 0, lo: 4}, false},
+		{Uint128{hi: 0, lo: 251}, true},
+		{Uint128{hi: 0, lo: 257}, true},
+		{Uint128{hi: 0, lo: 561}, false},                      // Carmichael number
+		{Uint128{hi: 0, lo: 2047}, false},                     // Strong pseudoprime to base 2
+		{Uint128{hi: 0, lo: 3215031751}, false},               // Strong pseudoprime to bases 2, 3, 5 and 7
+		{Uint128{hi: 0, lo: 3825123056546413051}, false},      // Strong pseudoprime to the first 9 prime bases
+		{Uint128{hi: 0, lo: maxUint64 - 58}, true},            // 2^64 - 59
+		{Uint128{hi: 0x2be69, lo: 0x51adc5b22410a5fd}, false}, // Strong pseudoprime to the first 13 prime bases
+		{Uint128{hi: 1<<25 - 1, lo: maxUint64}, true},         // 2^89 - 1
+		{Uint128{hi: maxInt64, lo: maxUint64}, true},          // 2^127 - 1
+		{Uint128{hi: maxUint64, lo: maxUint64 - 158}, true},   // 2^128 - 159
+		{Uint128{hi: maxUint64, lo: maxUint64 - 156}, false},
+		{Uint128{hi: maxUint64, lo: maxUint64}, false},
+	}
+	for _, test := range tests {
+		result := test.inp.IsPrime()
+		if test.expected != result {
+			t.Errorf("Expected %s.IsPrime() == %v, got: %v", test.inp, test.expected, result)
+		}
+	}
+}
+
+func TestIsPrimeRandUint128(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		x := randModUint128(r).Or(Uint128{lo: 1})
+		result := x.IsPrime()
+		expected := bigUint128(x).ProbablyPrime(20)
+		if result != expected {
+			t.Errorf("Expected %s.IsPrime() == %v, got: %v", x, expected, result)
+		}
+	}
+}
+
+func TestNextPrimeUint128(t *testing.T) {
+	tests := []struct {
+		inp       Uint128
+		expected1 Uint128
+		expected2 bool
+	}{
+		{Uint128{hi: 0, lo: 0}, Uint128{hi: 0, lo: 2}, true},
+		{Uint128{hi: 0, lo: 2}, Uint128{hi: 0, lo: 3}, true},
+		{Uint128{hi: 0, lo: 3}, Uint128{hi: 0, lo: 5}, true},
+		{Uint128{hi: 0, lo: 24}, Uint128{hi: 0, lo: 29}, true},
+		{Uint128{hi: 0, lo: maxUint64 - 58}, Uint128{hi: 1, lo: 13}, true},
+		{Uint128{hi: maxUint64, lo: maxUint64 - 170}, Uint128{hi: maxUint64, lo: maxUint64 - 158}, true},
+		{Uint128{hi: maxUint64, lo: maxUint64 - 158}, Uint128{}, false},
+		{Uint128{hi: maxUint64, lo: maxUint64}, Uint128{}, false},
+	}
+	for _, test := range tests {
+		result1, result2 := test.inp.NextPrime()
+		if result2 != test.expected2 || result2 && !result1.Eq(test.expected1) {
+			t.Errorf("Expected %s.NextPrime() == %s, %v got: %s, %v", test.inp, test.expected1, test.expected2, result1, result2)
+		}
+	}
+}
+
+func TestFactorizeUint128(t *testing.T) {
+	tests := []struct {
+		inp      Uint128
+		expected []Uint128
+	}{
+		{Uint128{hi: 0, lo: 0}, nil},
+		{Uint128{hi: 0, lo: 1}, nil},
+		{Uint128{hi: 0, lo: 2}, []Uint128{{lo: 2}}},
+		{Uint128{hi: 0, lo: 360}, []Uint128{{lo: 2}, {lo: 2}, {lo: 2}, {lo: 3}, {lo: 3}, {lo: 5}}},
+		{Uint128{hi: 0, lo: 257 * 257 * 263}, []Uint128{{lo: 257}, {lo: 257}, {lo: 263}}},
+		{Uint128{hi: 0, lo: 1 << 10}, []Uint128{{lo: 2}, {lo: 2}, {lo: 2}, {lo: 2}, {lo: 2}, {lo: 2}, {lo: 2}, {lo: 2}, {lo: 2}, {lo: 2}}},
+		// 2^128 - 1 = 3 * 5 * 17 * 257 * 641 * 65537 * 274177 * 6700417 * 67280421310721
+		{Uint128{hi: maxUint64, lo: maxUint64}, []Uint128{
+			{lo: 3}, {lo: 5}, {lo: 17}, {lo: 257}, {lo: 641}, {lo: 65537}, {lo: 274177}, {lo: 6700417}, {lo: 67280421310721},
+		}},
+		{Uint128{hi: maxInt64, lo: maxUint64}, []Uint128{{hi: maxInt64, lo: maxUint64}}},
+	}
+	for _, test := range tests {
+		result := test.inp.Factorize()
+		equal := len(result) == len(test.expected)
+		for i := 0; equal && i < len(result); i++ {
+			equal = result[i].Eq(test.expected[i])
+		}
+		if !equal {
+			t.Errorf("Expected %s.Factorize() == %v, got: %v", test.inp, test.expected, result)
+		}
+	}
+}
+
+func TestFactorizeRandUint128(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		// A product of primes of up to 32 bits each, which Pollard's rho splits quickly
+		x := Uint128{lo: 1}
+		for x.Len() < 88 {
+			p, _ := Uint128{lo: r.Uint64() >> 32}.NextPrime()
+			x = x.Mul(p)
+		}
+		factors := x.Factorize()
+		product := Uint128{lo: 1}
+		for j, p := range factors {
+			if !p.IsPrime() {
+				t.Errorf("%s.Factorize() returned non-prime factor %s", x, p)
+			}
+			if j > 0 && p.Lt(factors[j-1]) {
+				t.Errorf("%s.Factorize() returned unsorted factors %v", x, factors)
+			}
+			product = product.Mul(p)
+		}
+		if !product.Eq(x) {
+			t.Errorf("Expected product of %s.Factorize() == %s, got: %s", x, x, product)
+		}
+	}
+}
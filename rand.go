@@ -0,0 +1,144 @@
+package wide
+
+import "math/rand/v2"
+
+var (
+	// pcgMul is the default multiplier of the 128-bit PCG generators
+	pcgMul = Uint128{hi: 0x2360ed051fc65da4, lo: 0x4385df649fccf645}
+	// pcgCheapMul is the 64-bit "cheap" multiplier of PCG64DXSM, also used by its output function
+	pcgCheapMul = Uint128{lo: 0xda942042e4dd58b5}
+)
+
+// LCG128 is a 128-bit linear congruential generator, which returns the upper 64 bits of its state
+//
+// LCG128 implements rand.Source from math/rand/v2. It is fast, but its output is of lower statistical quality than that of the
+// PCG generators, which permute the same kind of state before returning it.
+type LCG128 struct {
+	state, mul, inc Uint128
+}
+
+// NewLCG128 returns an LCG128 with the given seed, multiplier and increment
+//
+// The generator has the full period 2^128 when mul % 4 == 1 and inc is odd. When inc is 0 it is a multiplicative
+// congruential generator, with a period of 2^126 when seed is odd and mul % 8 is 3 or 5.
+func NewLCG128(seed, mul, inc Uint128) *LCG128 {
+	return &LCG128{state: seed, mul: mul, inc: inc}
+}
+
+// NewMCG128 returns an LCG128 which is the multiplicative congruential generator of Lehmer, with the multiplier 0xda942042e4dd58b5
+//
+// The seed is made odd, which is required for the maximal period 2^126.
+func NewMCG128(seed Uint128) *LCG128 {
+	seed.lo |= 1
+	return &LCG128{state: seed, mul: pcgCheapMul}
+}
+
+// State returns the current state of an LCG128
+func (g *LCG128) State() Uint128 {
+	return g.state
+}
+
+// Uint64 advances the generator, and returns the upper 64 bits of its new state
+func (g *LCG128) Uint64() uint64 {
+	g.state = g.state.Mul(g.mul).Add(g.inc)
+	return g.state.hi
+}
+
+// PCG64 is the PCG-XSL-RR-128/64 generator of O'Neill, a 128-bit LCG with a permuted 64-bit output
+//
+// PCG64 implements rand.Source from math/rand/v2. It produces the same sequence as pcg64_random_r of the reference C library.
+type PCG64 struct {
+	state, inc Uint128
+}
+
+// NewPCG64 returns a PCG64 with the given seed and stream, seeded as by pcg64_srandom_r
+func NewPCG64(seed, seq Uint128) *PCG64 {
+	p := new(PCG64)
+	p.Seed(seed, seq)
+	return p
+}
+
+// Seed resets a PCG64 to the given seed and stream, as by pcg64_srandom_r
+//
+// Only the lower 127 bits of seq are significant, since the increment is always odd.
+func (p *PCG64) Seed(seed, seq Uint128) {
+	p.state, p.inc = Uint128{}, seq.LShift()
+	p.inc.lo |= 1
+	p.Uint64()
+	p.state = p.state.Add(seed)
+	p.Uint64()
+}
+
+// Uint64 advances the generator, and returns its new state permuted by a xor-shift and a random rotation
+func (p *PCG64) Uint64() uint64 {
+	p.state = p.state.Mul(pcgMul).Add(p.inc)
+	x := p.state.hi ^ p.state.lo
+	r := p.state.hi >> 58
+	return x>>r | x<<(-r&63)
+}
+
+// PCG64DXSM is the PCG generator of O'Neill with the "double xor-shift multiply" output function, as in NumPy's PCG64DXSM
+//
+// PCG64DXSM implements rand.Source from math/rand/v2. Unlike PCG64, its state is advanced with a 64-bit multiplier, and its
+// output is a permutation of the state before it is advanced. It differs from the PCG of math/rand/v2, which uses a
+// 128-bit multiplier.
+type PCG64DXSM struct {
+	state, inc Uint128
+}
+
+// NewPCG64DXSM returns a PCG64DXSM with the given seed and stream, seeded as by pcg_cm_srandom_r
+func NewPCG64DXSM(seed, seq Uint128) *PCG64DXSM {
+	p := new(PCG64DXSM)
+	p.Seed(seed, seq)
+	return p
+}
+
+// Seed resets a PCG64DXSM to the given seed and stream, as by pcg_cm_srandom_r
+//
+// Only the lower 127 bits of seq are significant, since the increment is always odd.
+func (p *PCG64DXSM) Seed(seed, seq Uint128) {
+	p.state, p.inc = Uint128{}, seq.LShift()
+	p.inc.lo |= 1
+	p.Uint64()
+	p.state = p.state.Add(seed)
+	p.Uint64()
+}
+
+// Uint64 returns the state of the generator permuted by a xor-shift multiply, and then advances it
+func (p *PCG64DXSM) Uint64() uint64 {
+	hi, lo := p.state.hi, p.state.lo|1
+	hi ^= hi >> 32
+	hi *= pcgCheapMul.lo
+	hi ^= hi >> 48
+	hi *= lo
+	p.state = p.state.Mul(pcgCheapMul).Add(p.inc)
+	return hi
+}
+
+// RandInt128 returns a pseudo-random Int128 from r
+func RandInt128(r *rand.Rand) (z Int128) {
+	z.hi = int64(r.Uint64())
+	z.lo = r.Uint64()
+	return z
+}
+
+// Uint128N returns a pseudo-random Uint128 from r in the half-open interval [0, n), without modulo bias
+//
+// Uint128N uses Lemire's multiply-and-reject method, which only needs a division when a random number might be rejected. It panics
+// if n is 0.
+func Uint128N(r *rand.Rand, n Uint128) Uint128 {
+	if n.hi == 0 && n.lo == 0 {
+		panic("invalid argument to Uint128N")
+	}
+	x := Uint128{hi: r.Uint64(), lo: r.Uint64()}
+	hi, lo := x.mulFull(n)
+	if lo.Lt(n) {
+		// The smallest low half which is unbiased is 2^128 % n
+		t := n.Neg().Mod(n)
+		for lo.Lt(t) {
+			x = Uint128{hi: r.Uint64(), lo: r.Uint64()}
+			hi, lo = x.mulFull(n)
+		}
+	}
+	return hi
+}
@@ -0,0 +1,130 @@
+package wide
+
+import (
+	"math/big"
+	"math/rand/v2"
+	"testing"
+)
+
+var (
+	_ rand.Source = (*LCG128)(nil)
+	_ rand.Source = (*PCG64)(nil)
+	_ rand.Source = (*PCG64DXSM)(nil)
+)
+
+// bigLCG advances a big.Int model of a 128-bit LCG state
+func bigLCG(state, mul, inc *big.Int) {
+	mod := new(big.Int).Lsh(big.NewInt(1), 128)
+	state.Mul(state, mul).Add(state, inc).Mod(state, mod)
+}
+
+func TestPCG64(t *testing.T) {
+	// Reference output of pcg64_random_r after pcg64_srandom_r(42, 54), from the PCG C library
+	expected := []uint64{
+		0x86b1da1d72062b68, 0x1304aa46c9853d39, 0xa3670e9e0dd50358,
+		0xf9090e529a7dae00, 0xc85b9fd837996f2c, 0x606121f8e3919196,
+	}
+	p := NewPCG64(Uint128FromUint64(42), Uint128FromUint64(54))
+	for i, e := range expected {
+		if result := p.Uint64(); result != e {
+			t.Errorf("Expected PCG64 output %d == %#x, got: %#x", i, e, result)
+		}
+	}
+}
+
+func TestGeneratorsBig(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	mask64 := new(big.Int).SetUint64(1<<64 - 1)
+	for i := 0; i < 20; i++ {
+		seed, seq := NewUint128(r.Uint64(), r.Uint64()), NewUint128(r.Uint64(), r.Uint64())
+		mul, inc := NewUint128(r.Uint64(), r.Uint64()|1), NewUint128(r.Uint64(), r.Uint64())
+		lcg, pcg, dxsm := NewLCG128(seed, mul, inc), NewPCG64(seed, seq), NewPCG64DXSM(seed, seq)
+		bigMul, bigInc := bigUint128(mul), bigUint128(inc)
+		bigLCGState := bigUint128(seed)
+		bigPCGState, bigDXSMState := bigUint128(pcg.state), bigUint128(dxsm.state)
+		bigPCGInc := bigUint128(seq.LShift().Or(Uint128FromUint64(1)))
+		for j := 0; j < 100; j++ {
+			bigLCG(bigLCGState, bigMul, bigInc)
+			if result, expected := lcg.Uint64(), new(big.Int).Rsh(bigLCGState, 64).Uint64(); result != expected {
+				t.Fatalf("Expected LCG128 output %d == %#x, got: %#x", j, expected, result)
+			}
+
+			bigLCG(bigPCGState, bigUint128(pcgMul), bigPCGInc)
+			hi, lo := new(big.Int).Rsh(bigPCGState, 64).Uint64(), new(big.Int).And(bigPCGState, mask64).Uint64()
+			x, rot := hi^lo, hi>>58
+			if result, expected := pcg.Uint64(), x>>rot|x<<((64-rot)%64); result != expected {
+				t.Fatalf("Expected PCG64 output %d == %#x, got: %#x", j, expected, result)
+			}
+
+			hi, lo = new(big.Int).Rsh(bigDXSMState, 64).Uint64(), new(big.Int).And(bigDXSMState, mask64).Uint64()
+			hi ^= hi >> 32
+			hi *= 0xda942042e4dd58b5
+			hi ^= hi >> 48
+			hi *= lo | 1
+			bigLCG(bigDXSMState, new(big.Int).SetUint64(0xda942042e4dd58b5), bigPCGInc)
+			if result := dxsm.Uint64(); result != hi {
+				t.Fatalf("Expected PCG64DXSM output %d == %#x, got: %#x", j, hi, result)
+			}
+		}
+	}
+}
+
+func TestMCG128(t *testing.T) {
+	g := NewMCG128(NewUint128(0, 2))
+	if state := g.State(); state != NewUint128(0, 3) {
+		t.Errorf("Expected NewMCG128(2).State() == 0x3, got: %s", state)
+	}
+	// 3 * 0xda942042e4dd58b5 == 0x28fbc60c8ae980a1f
+	if result := g.Uint64(); result != 2 {
+		t.Errorf("Expected first MCG128 output == 0x2, got: %#x", result)
+	}
+	if state, expected := g.State(), NewUint128(2, 0x8fbc60c8ae980a1f); state != expected {
+		t.Errorf("Expected MCG128 state == %s, got: %s", expected, state)
+	}
+}
+
+func TestRandInt128(t *testing.T) {
+	r1, r2 := rand.New(NewPCG64DXSM(Uint128FromUint64(1), Uint128{})), rand.New(NewPCG64DXSM(Uint128FromUint64(1), Uint128{}))
+	for i := 0; i < 100; i++ {
+		result := RandInt128(r1)
+		hi, lo := r2.Uint64(), r2.Uint64()
+		if expected := (Int128{hi: int64(hi), lo: lo}); result != expected {
+			t.Errorf("Expected RandInt128 output %d == %s, got: %s", i, expected, result)
+		}
+	}
+}
+
+func TestUint128N(t *testing.T) {
+	r := rand.New(NewPCG64(Uint128FromUint64(1), Uint128FromUint64(2)))
+	bounds := []Uint128{
+		{lo: 1}, {lo: 2}, {lo: 3}, {lo: 1000}, {hi: 1}, {hi: 1 << 63, lo: 1},
+		{hi: 1<<64 - 1, lo: 1<<64 - 1}, {hi: 0x5555555555555555, lo: 0x5555555555555556},
+	}
+	for _, n := range bounds {
+		for i := 0; i < 1000; i++ {
+			if result := Uint128N(r, n); !result.Lt(n) {
+				t.Fatalf("Expected Uint128N(%s) < %s, got: %s", n, n, result)
+			}
+		}
+	}
+	// Reducing a random number modulo 3*2^126 would return values below 2^126 twice as often as the others
+	n := Uint128{hi: 3 << 62}
+	var counts [3]int
+	for i := 0; i < 30000; i++ {
+		counts[Uint128N(r, n).hi>>62]++
+	}
+	for i, c := range counts {
+		if c < 9000 || c > 11000 {
+			t.Errorf("Expected Uint128N(%s) to be uniform, got %d results in third %d", n, c, i)
+		}
+	}
+}
+
+func TestUint128NPanic(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Expected Uint128N(0) to panic")
+		}
+	}()
+	Uint128N(rand.New(rand.NewPCG(1, 2)), Uint128{})
+}
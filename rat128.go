@@ -0,0 +1,307 @@
+package wide
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// Rat128 is an exact rational number, with an Int128 numerator and a positive Int128 denominator in lowest terms
+//
+// Unlike big.Rat, a Rat128 never allocates. Arithmetic reports whether its result is representable instead of overflowing.
+// The zero value is 0.
+type Rat128 struct {
+	num Int128
+	den Uint128 // 0 means 1, so that the zero value is 0/1
+}
+
+// NewRat128 returns the ratio a/b in lowest terms, and whether it is representable
+//
+// NewRat128 panics on division by 0. The only unrepresentable ratios are those which normalize to a denominator of 2^127, or to a
+// numerator of +2^127 (e.g. MinInt128/-1).
+func NewRat128(a, b Int128) (z Rat128, ok bool) {
+	if b.Sign() == 0 {
+		panic("runtime error: integer divide by zero")
+	}
+	return newRat128(a.IsNeg() != b.IsNeg(), a.Abs().Uint128(), b.Abs().Uint128())
+}
+
+// ParseRat128 parses a string of the form "[+-]digits[/digits]" as a Rat128, in lowest terms
+func ParseRat128(s string) (Rat128, error) {
+	var x Rat128
+	if err := x.UnmarshalText([]byte(s)); err != nil {
+		return Rat128{}, err
+	}
+	return x, nil
+}
+
+// Rat128FromBigRat returns a big.Rat as a Rat128, and whether it is representable
+func Rat128FromBigRat(r *big.Rat) (z Rat128, ok bool) {
+	n, d := r.Num(), r.Denom()
+	if n.BitLen() > int128Size || d.BitLen() > int128Size {
+		return Rat128{}, false
+	}
+	return packRat128(n.Sign() < 0, Uint128FromBigInt(new(big.Int).Abs(n)), Uint128FromBigInt(d))
+}
+
+// Add returns the sum of two Rat128's, and whether it is representable
+//
+// In rare cases, ok is false because an intermediate product overflows even though the sum is representable.
+func (x Rat128) Add(y Rat128) (z Rat128, ok bool) {
+	xneg, xn, xd := x.parts()
+	yneg, yn, yd := y.parts()
+	return addRat128(xneg, xn, xd, yneg, yn, yd)
+}
+
+// BigRat returns a Rat128 as a big.Rat
+func (x Rat128) BigRat() *big.Rat {
+	neg, n, d := x.parts()
+	num := n.bigInt()
+	if neg {
+		num.Neg(num)
+	}
+	return new(big.Rat).SetFrac(num, d.bigInt())
+}
+
+// Cmp compares x and y and returns:
+//
+//   -1 if x <  y
+//    0 if x == y
+//   +1 if x >  y
+func (x Rat128) Cmp(y Rat128) int {
+	if xs, ys := x.Sign(), y.Sign(); xs != ys || xs == 0 {
+		switch {
+		case xs < ys:
+			return -1
+		case xs > ys:
+			return 1
+		default:
+			return 0
+		}
+	}
+	// Compare xn*yd with yn*xd, which may need 256 bits
+	_, xn, xd := x.parts()
+	_, yn, yd := y.parts()
+	lhi, llo := xn.mulFull(yd)
+	rhi, rlo := yn.mulFull(xd)
+	return cmp256(lhi, llo, rhi, rlo) * x.Sign()
+}
+
+// Denom returns the denominator of a Rat128, which is always positive
+func (x Rat128) Denom() Int128 {
+	_, _, d := x.parts()
+	return d.Int128()
+}
+
+// Float64 returns the nearest float64 to a Rat128, and whether it is exact
+func (x Rat128) Float64() (f float64, exact bool) {
+	neg, n, d := x.parts()
+	if n.hi == 0 && n.lo == 0 {
+		return 0, true
+	}
+	// Normalize both to 127 bits, so that their quotient has 127 or 128 bits
+	ln, ld := n.Len(), d.Len()
+	n, d = n.LShiftN(int128Size-1-ln), d.LShiftN(int128Size-1-ld)
+	q, r := divMod256(n.RShift(), n.LShiftN(int128Size-1), d)
+	exact = r.hi == 0 && r.lo == 0 && q.Len()-q.TrailingZeros() <= 53
+	if r.hi != 0 || r.lo != 0 {
+		q.lo |= 1 // sticky bit
+	}
+	e, m := roundFloat(neg, int(ln)-int(ld)-(int128Size-1), q, RoundHalfEven, 53, -1022)
+	f = math.Float64frombits(uint64(e)<<52 + m.lo)
+	if neg {
+		return -f, exact
+	}
+	return f, exact
+}
+
+// IsInt reports whether the denominator of a Rat128 is 1
+func (x Rat128) IsInt() bool {
+	return x.den.hi == 0 && x.den.lo <= 1
+}
+
+// MarshalText implements the encoding.TextMarshaler interface
+func (x Rat128) MarshalText() ([]byte, error) {
+	return []byte(x.String()), nil
+}
+
+// Mul returns the product of two Rat128's, and whether it is representable
+func (x Rat128) Mul(y Rat128) (z Rat128, ok bool) {
+	xneg, xn, xd := x.parts()
+	yneg, yn, yd := y.parts()
+	return mulRat128(xneg != yneg, xn, xd, yn, yd)
+}
+
+// Neg returns the additive inverse of a Rat128
+//
+// This function overflows silently
+func (x Rat128) Neg() Rat128 {
+	x.num = x.num.Neg()
+	return x
+}
+
+// Num returns the numerator of a Rat128
+func (x Rat128) Num() Int128 {
+	return x.num
+}
+
+// parts returns the sign, and the magnitudes of the numerator and denominator, of a Rat128
+func (x Rat128) parts() (neg bool, n, d Uint128) {
+	d = x.den
+	if d.hi == 0 && d.lo == 0 {
+		d.lo = 1
+	}
+	return x.num.IsNeg(), x.num.Abs().Uint128(), d
+}
+
+// Quo returns the quotient of two Rat128's, and whether it is representable
+//
+// Quo panics on division by 0.
+func (x Rat128) Quo(y Rat128) (z Rat128, ok bool) {
+	if y.Sign() == 0 {
+		panic("runtime error: integer divide by zero")
+	}
+	xneg, xn, xd := x.parts()
+	yneg, yn, yd := y.parts()
+	return mulRat128(xneg != yneg, xn, xd, yd, yn)
+}
+
+// Sign returns the sign of a Rat128
+func (x Rat128) Sign() int {
+	return x.num.Sign()
+}
+
+// String returns a Rat128 in the form "a/b", even if b is 1
+func (x Rat128) String() string {
+	neg, n, d := x.parts()
+	var buf []byte
+	if neg {
+		buf = append(buf, '-')
+	}
+	buf = appendDecimal(buf, n)
+	buf = append(buf, '/')
+	return string(appendDecimal(buf, d))
+}
+
+// Sub returns the difference of two Rat128's, and whether it is representable
+//
+// In rare cases, ok is false because an intermediate product overflows even though the difference is representable.
+func (x Rat128) Sub(y Rat128) (z Rat128, ok bool) {
+	xneg, xn, xd := x.parts()
+	yneg, yn, yd := y.parts()
+	return addRat128(xneg, xn, xd, !yneg, yn, yd)
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface, see ParseRat128
+func (x *Rat128) UnmarshalText(text []byte) error {
+	s := text
+	neg := false
+	if len(s) > 0 && (s[0] == '-' || s[0] == '+') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+	num, den := s, []byte("1")
+	if i := bytes.IndexByte(s, '/'); i >= 0 {
+		num, den = s[:i], s[i+1:]
+	}
+	n, ok1 := parseDigits128(num)
+	d, ok2 := parseDigits128(den)
+	switch {
+	case !ok1 || !ok2:
+		return errors.New("wide: invalid rational " + strconv.Quote(string(text)))
+	case d.hi == 0 && d.lo == 0:
+		return errors.New("wide: rational with zero denominator " + strconv.Quote(string(text)))
+	}
+	z, ok := newRat128(neg, n, d)
+	if !ok {
+		return errors.New("wide: rational out of range " + strconv.Quote(string(text)))
+	}
+	*x = z
+	return nil
+}
+
+// addRat128 returns the sum of the rationals ±xn/xd and ±yn/yd, which must be in lowest terms, and whether it is representable
+func addRat128(xneg bool, xn, xd Uint128, yneg bool, yn, yd Uint128) (z Rat128, ok bool) {
+	// Knuth's algorithm, which only needs a second GCD with the (usually small) GCD of the denominators
+	g := xd.GCD(yd)
+	xd1, yd1 := xd.Div(g), yd.Div(g)
+	hi1, t1 := xn.mulFull(yd1)
+	hi2, t2 := yn.mulFull(xd1)
+	if hi1.hi != 0 || hi1.lo != 0 || hi2.hi != 0 || hi2.lo != 0 {
+		return Rat128{}, false
+	}
+	var t Uint128
+	neg := xneg
+	switch {
+	case xneg == yneg:
+		if t = t1.Add(t2); t.Lt(t1) {
+			return Rat128{}, false
+		}
+	case t1.Gte(t2):
+		t = t1.Sub(t2)
+	default:
+		t = t2.Sub(t1)
+		neg = yneg
+	}
+	if t.hi == 0 && t.lo == 0 {
+		return Rat128{}, true
+	}
+	g = t.GCD(g)
+	hi, d := xd1.mulFull(yd.Div(g))
+	if hi.hi != 0 || hi.lo != 0 {
+		return Rat128{}, false
+	}
+	return packRat128(neg, t.Div(g), d)
+}
+
+// mulRat128 returns the product of the rationals xn/xd and yn/yd, which must be in lowest terms, with the given sign, and whether
+// it is representable
+func mulRat128(neg bool, xn, xd, yn, yd Uint128) (z Rat128, ok bool) {
+	if xn.hi == 0 && xn.lo == 0 || yn.hi == 0 && yn.lo == 0 {
+		return Rat128{}, true
+	}
+	// Cancelling common factors first means the product is already in lowest terms
+	g1, g2 := xn.GCD(yd), yn.GCD(xd)
+	nhi, n := xn.Div(g1).mulFull(yn.Div(g2))
+	dhi, d := xd.Div(g2).mulFull(yd.Div(g1))
+	if nhi.hi != 0 || nhi.lo != 0 || dhi.hi != 0 || dhi.lo != 0 {
+		return Rat128{}, false
+	}
+	return packRat128(neg, n, d)
+}
+
+// newRat128 returns the ratio ±n/d in lowest terms, for a non-zero d, and whether it is representable
+func newRat128(neg bool, n, d Uint128) (z Rat128, ok bool) {
+	if g := n.GCD(d); g.hi != 0 || g.lo != 1 {
+		n, d = n.Div(g), d.Div(g)
+	}
+	return packRat128(neg, n, d)
+}
+
+// packRat128 returns the ratio ±n/d, which must be in lowest terms, and whether it is representable
+func packRat128(neg bool, n, d Uint128) (z Rat128, ok bool) {
+	if d.hi>>63 != 0 || n.hi>>63 != 0 && (!neg || n.hi != 1<<63 || n.lo != 0) {
+		return Rat128{}, false
+	}
+	if d.hi == 0 && d.lo == 1 {
+		d = Uint128{}
+	}
+	return Rat128{num: signed128(n, neg), den: d}, true
+}
+
+// parseDigits128 parses a non-empty string of decimal digits as a Uint128, and reports whether it is valid and in range
+func parseDigits128(s []byte) (Uint128, bool) {
+	var x Uint128
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return Uint128{}, false
+		}
+		hi, lo := x.mulFull(Uint128{lo: 10})
+		if x = lo.Add(Uint128{lo: uint64(c - '0')}); hi.lo != 0 || x.Lt(lo) {
+			return Uint128{}, false
+		}
+	}
+	return x, len(s) > 0
+}
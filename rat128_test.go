@@ -0,0 +1,174 @@
+package wide
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+// randRat128 returns a random Rat128 in lowest terms, with numerator and denominator of random bit lengths
+func randRat128(r *rand.Rand) Rat128 {
+	for {
+		d := randModUint128(r).RShift().Int128()
+		if d.Sign() == 0 {
+			continue
+		}
+		if z, ok := NewRat128(randNumTheoryInt128(r), d); ok {
+			return z
+		}
+	}
+}
+
+// representable reports whether a big.Rat is representable as a Rat128
+func representable(x *big.Rat) bool {
+	_, ok := Rat128FromBigRat(x)
+	return ok
+}
+
+func TestNewRat128(t *testing.T) {
+	tests := []struct {
+		a, b     Int128
+		expected string
+		ok       bool
+	}{
+		{Int128FromInt64(0), Int128FromInt64(-5), "0/1", true},
+		{Int128FromInt64(6), Int128FromInt64(4), "3/2", true},
+		{Int128FromInt64(6), Int128FromInt64(-4), "-3/2", true},
+		{Int128FromInt64(-6), Int128FromInt64(-4), "3/2", true},
+		{Int128{hi: minInt64, lo: 0}, Int128FromInt64(2), "-85070591730234615865843651857942052864/1", true},
+		{Int128{hi: minInt64, lo: 0}, Int128{hi: minInt64, lo: 0}, "1/1", true},
+		{Int128{hi: minInt64, lo: 0}, Int128FromInt64(-1), "", false},
+		{Int128FromInt64(1), Int128{hi: minInt64, lo: 0}, "", false},
+		{Int128FromInt64(2), Int128{hi: minInt64, lo: 0}, "-1/85070591730234615865843651857942052864", true},
+	}
+	for _, test := range tests {
+		result, ok := NewRat128(test.a, test.b)
+		if ok != test.ok || ok && result.String() != test.expected {
+			t.Errorf("Expected NewRat128(%s, %s) == %s, %v got: %s, %v", test.a, test.b, test.expected, test.ok, result, ok)
+		}
+	}
+}
+
+func TestNewRat128Panic(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Expected NewRat128(1, 0) to panic")
+		}
+	}()
+	NewRat128(Int128FromInt64(1), Int128{})
+}
+
+func TestParseRat128(t *testing.T) {
+	tests := []struct {
+		inp      string
+		expected string
+	}{
+		{"0", "0/1"},
+		{"-0/7", "0/1"},
+		{"+5", "5/1"},
+		{"30000/1001", "30000/1001"},
+		{"-24000/1001", "-24000/1001"},
+		{"10/4", "5/2"},
+		{"170141183460469231731687303715884105727/3", "170141183460469231731687303715884105727/3"},
+		{"-170141183460469231731687303715884105728", "-170141183460469231731687303715884105728/1"},
+		{"340282366920938463463374607431768211455/340282366920938463463374607431768211455", "1/1"},
+	}
+	for _, test := range tests {
+		result, err := ParseRat128(test.inp)
+		if err != nil || result.String() != test.expected {
+			t.Errorf("Expected ParseRat128(%q) == %s, got: %s, %v", test.inp, test.expected, result, err)
+		}
+	}
+	for _, inp := range []string{"", "-", "/", "1/", "/2", "1/0", "1/-2", "1.5", "1/2/3", " 1", "170141183460469231731687303715884105728",
+		"340282366920938463463374607431768211456/2"} {
+		if result, err := ParseRat128(inp); err == nil {
+			t.Errorf("Expected ParseRat128(%q) to fail, got: %s", inp, result)
+		}
+	}
+}
+
+func TestArithRat128(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		x, y := randRat128(r), randRat128(r)
+		if i%2 == 0 {
+			// Small values, whose sums and products never overflow an intermediate product
+			x, _ = NewRat128(Int128FromInt64(r.Int63n(1<<40)-1<<39), Int128FromInt64(r.Int63n(1<<40)+1))
+			y, _ = NewRat128(Int128FromInt64(r.Int63n(1<<40)-1<<39), Int128FromInt64(r.Int63n(1<<40)+1))
+		}
+		bx, by := x.BigRat(), y.BigRat()
+		ops := []struct {
+			name     string
+			result   func(Rat128) (Rat128, bool)
+			expected *big.Rat
+			exact    bool // whether ok must be true for every representable result
+		}{
+			{"Add", x.Add, new(big.Rat).Add(bx, by), i%2 == 0},
+			{"Sub", x.Sub, new(big.Rat).Sub(bx, by), i%2 == 0},
+			{"Mul", x.Mul, new(big.Rat).Mul(bx, by), true},
+		}
+		if y.Sign() != 0 {
+			ops = append(ops, struct {
+				name     string
+				result   func(Rat128) (Rat128, bool)
+				expected *big.Rat
+				exact    bool
+			}{"Quo", x.Quo, new(big.Rat).Quo(bx, by), true})
+		}
+		for _, op := range ops {
+			result, ok := op.result(y)
+			switch {
+			case ok && result.BigRat().Cmp(op.expected) != 0:
+				t.Errorf("Expected %s.%s(%s) == %s, got: %s", x, op.name, y, op.expected, result)
+			case ok && !representable(op.expected):
+				t.Errorf("Expected %s.%s(%s) to overflow, got: %s", x, op.name, y, result)
+			case !ok && op.exact && representable(op.expected):
+				t.Errorf("Expected %s.%s(%s) == %s, got overflow", x, op.name, y, op.expected)
+			}
+		}
+	}
+}
+
+func TestCmpRat128(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		x, y := randRat128(r), randRat128(r)
+		if i%10 == 0 {
+			y = x
+		}
+		if result, expected := x.Cmp(y), x.BigRat().Cmp(y.BigRat()); result != expected {
+			t.Errorf("Expected %s.Cmp(%s) == %v, got: %v", x, y, expected, result)
+		}
+	}
+}
+
+func TestFloat64Rat128(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		x := randRat128(r)
+		if i%10 == 0 {
+			x, _ = NewRat128(randNumTheoryInt128(r), Int128{lo: 1 << uint(r.Intn(64))})
+		}
+		f, exact := x.Float64()
+		if ef, eexact := x.BigRat().Float64(); f != ef || exact != eexact {
+			t.Errorf("Expected %s.Float64() == %v, %v got: %v, %v", x, ef, eexact, f, exact)
+		}
+	}
+}
+
+func TestBigRatRat128(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		x := randRat128(r)
+		if result, ok := Rat128FromBigRat(x.BigRat()); !ok || result != x {
+			t.Errorf("Expected Rat128FromBigRat(%s) == %s, got: %s, %v", x, x, result, ok)
+		}
+	}
+	huge := new(big.Rat).SetFrac(new(big.Int).Lsh(big.NewInt(1), 127), big.NewInt(3))
+	if result, ok := Rat128FromBigRat(huge); ok {
+		t.Errorf("Expected Rat128FromBigRat(%s) to overflow, got: %s", huge, result)
+	}
+	if result, ok := Rat128FromBigRat(new(big.Rat).Neg(huge)); !ok || result.String() != "-170141183460469231731687303715884105728/3" {
+		t.Errorf("Expected Rat128FromBigRat(-%s) == -%s, got: %s, %v", huge, huge, result, ok)
+	}
+}
@@ -0,0 +1,160 @@
+package wide
+
+// Montgomery128 is a context for repeated modular arithmetic with a fixed, odd modulus, using Montgomery reduction
+//
+// Values are operated on in Montgomery form (i.e. x*R mod m, where R = 2^128), see ToMont and FromMont.
+type Montgomery128 struct {
+	m    Uint128 // modulus
+	mInv Uint128 // -m^-1 mod R
+	r    Uint128 // R mod m
+	r2   Uint128 // R^2 mod m
+}
+
+// NewMontgomery128 returns a Montgomery128 context for the modulus m
+//
+// NewMontgomery128 panics if m is even.
+func NewMontgomery128(m Uint128) *Montgomery128 {
+	if m.lo&1 == 0 {
+		panic("even modulus")
+	}
+	// Newton's method doubles the number of correct bits with each iteration, and m*m = 1 (mod 8) for all odd m
+	inv := m
+	for i := 0; i < 6; i++ {
+		_, mInv := m.mulFull(inv)
+		_, inv = inv.mulFull(Uint128{lo: 2}.Sub(mInv))
+	}
+	r := m.Neg().Mod(m)
+	return &Montgomery128{
+		m:    m,
+		mInv: inv.Neg(),
+		r:    r,
+		r2:   r.MulMod(r, m),
+	}
+}
+
+// FromMont converts x out of Montgomery form
+func (c *Montgomery128) FromMont(x Uint128) Uint128 {
+	return c.redc(Uint128{}, x)
+}
+
+// Modulus returns the modulus of the context
+func (c *Montgomery128) Modulus() Uint128 {
+	return c.m
+}
+
+// Mul returns the Montgomery product of x and y, which must both be in Montgomery form and less than the modulus
+func (c *Montgomery128) Mul(x, y Uint128) Uint128 {
+	hi, lo := x.mulFull(y)
+	return c.redc(hi, lo)
+}
+
+// Pow returns x raised to the power e, where x and the result are in Montgomery form
+func (c *Montgomery128) Pow(x, e Uint128) (z Uint128) {
+	z = c.r
+	for i := e.Len(); i > 0; i-- {
+		z = c.Mul(z, z)
+		if e.RShiftN(i-1).lo&1 != 0 {
+			z = c.Mul(z, x)
+		}
+	}
+	return z
+}
+
+// redc returns (hi, lo) / R mod m, for any 256-bit (hi, lo) less than m*R
+func (c *Montgomery128) redc(hi, lo Uint128) Uint128 {
+	_, u := lo.mulFull(c.mInv)
+	uhi, ulo := u.mulFull(c.m)
+	// The low halves of (hi, lo) and u*m cancel out, so all that survives is the carry
+	if ulo.Add(lo).Lt(lo) {
+		hi = hi.Inc()
+	}
+	z := hi.Add(uhi)
+	if z.Lt(hi) || z.Gte(c.m) {
+		z = z.Sub(c.m)
+	}
+	return z
+}
+
+// ToMont converts x into Montgomery form
+func (c *Montgomery128) ToMont(x Uint128) Uint128 {
+	hi, lo := x.mulFull(c.r2)
+	return c.redc(hi, lo)
+}
+
+// Barrett128 is a context for repeated modular arithmetic with a fixed modulus, using Barrett reduction
+//
+// Unlike Montgomery128, any non-zero modulus is supported (including even moduli), and values are operated on in their
+// ordinary form. Reduction follows Möller and Granlund's "Improved division by invariant integers", which is Barrett
+// reduction with a reciprocal precomputed from the normalized modulus.
+type Barrett128 struct {
+	m Uint128 // modulus
+	d Uint128 // normalized modulus, i.e. m << s
+	v Uint128 // reciprocal, i.e. floor((2^256 - 1) / d) - 2^128
+	s uint    // normalization shift
+}
+
+// NewBarrett128 returns a Barrett128 context for the modulus m
+//
+// NewBarrett128 panics if m is 0.
+func NewBarrett128(m Uint128) *Barrett128 {
+	if m.hi == 0 && m.lo == 0 {
+		panic("runtime error: integer divide by zero")
+	}
+	s := int128Size - m.Len()
+	d := m.LShiftN(s)
+	v, _ := divMod256(d.Not(), Uint128{hi: maxUint64, lo: maxUint64}, d)
+	return &Barrett128{m: m, d: d, v: v, s: s}
+}
+
+// Modulus returns the modulus of the context
+func (c *Barrett128) Modulus() Uint128 {
+	return c.m
+}
+
+// Mul returns the product of x and y modulo m
+func (c *Barrett128) Mul(x, y Uint128) Uint128 {
+	hi, lo := x.mulFull(y)
+	return c.reduce(c.Reduce(hi), lo)
+}
+
+// Pow returns x raised to the power e, modulo m
+func (c *Barrett128) Pow(x, e Uint128) (z Uint128) {
+	z = c.Reduce(Uint128{lo: 1})
+	x = c.Reduce(x)
+	for i := e.Len(); i > 0; i-- {
+		z = c.Mul(z, z)
+		if e.RShiftN(i-1).lo&1 != 0 {
+			z = c.Mul(z, x)
+		}
+	}
+	return z
+}
+
+// Reduce returns x modulo m
+func (c *Barrett128) Reduce(x Uint128) Uint128 {
+	return c.reduce(Uint128{}, x)
+}
+
+// reduce returns the 256-bit (hi, lo) modulo m, for any hi less than m
+func (c *Barrett128) reduce(hi, lo Uint128) Uint128 {
+	// Normalize the dividend, which cannot overflow since hi < m
+	u1 := hi.LShiftN(c.s).Or(lo.RShiftN(int128Size - c.s))
+	u0 := lo.LShiftN(c.s)
+	// Estimate the quotient (q1, q0) = v*u1 + (u1, u0)
+	q1, q0 := c.v.mulFull(u1)
+	q0 = q0.Add(u0)
+	if q0.Lt(u0) {
+		q1 = q1.Inc()
+	}
+	q1 = q1.Add(u1).Inc()
+	// The estimate is off by at most one in either direction
+	_, qd := q1.mulFull(c.d)
+	r := u0.Sub(qd)
+	if r.Gt(q0) {
+		r = r.Add(c.d)
+	}
+	if r.Gte(c.d) {
+		r = r.Sub(c.d)
+	}
+	return r.RShiftN(c.s)
+}
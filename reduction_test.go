@@ -0,0 +1,158 @@
+package wide
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestMontgomery128(t *testing.T) {
+	moduli := []Uint128{
+		{hi: 0, lo: 1},
+		{hi: 0, lo: 3},
+		{hi: 0, lo: maxUint64},
+		{hi: maxInt64, lo: maxUint64},
+		{hi: maxUint64, lo: maxUint64},
+		{hi: maxUint64, lo: maxUint64 - 158},
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		moduli = append(moduli, randModUint128(r).Or(Uint128{lo: 1}))
+	}
+	for _, m := range moduli {
+		c := NewMontgomery128(m)
+		if !c.Modulus().Eq(m) {
+			t.Errorf("Expected NewMontgomery128(%s).Modulus() == %s, got: %s", m, m, c.Modulus())
+		}
+		for i := 0; i < 10; i++ {
+			x, y, e := randModUint128(r), randModUint128(r), randModUint128(r)
+			xm, ym := c.ToMont(x), c.ToMont(y)
+			if result, expected := c.FromMont(xm), x.Mod(m); !result.Eq(expected) {
+				t.Errorf("Expected FromMont(ToMont(%s)) == %s mod %s, got: %s", x, expected, m, result)
+			}
+			if result, expected := c.FromMont(c.Mul(xm, ym)), x.MulMod(y, m); !result.Eq(expected) {
+				t.Errorf("Expected %s*%s mod %s == %s, got: %s", x, y, m, expected, result)
+			}
+			if result, expected := c.FromMont(c.Pow(xm, e)), x.PowMod(e, m); !result.Eq(expected) {
+				t.Errorf("Expected %s^%s mod %s == %s, got: %s", x, e, m, expected, result)
+			}
+		}
+	}
+}
+
+func TestMontgomery128Even(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Even modulus did not panic")
+		}
+	}()
+	NewMontgomery128(Uint128{hi: 0, lo: 2})
+}
+
+func TestBarrett128(t *testing.T) {
+	moduli := []Uint128{
+		{hi: 0, lo: 1},
+		{hi: 0, lo: 2},
+		{hi: 0, lo: 10},
+		{hi: 0, lo: 1 << 63},
+		{hi: 1, lo: 0},
+		{hi: 1 << 63, lo: 0},
+		{hi: maxUint64, lo: maxUint64},
+		{hi: maxUint64, lo: maxUint64 - 1},
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		moduli = append(moduli, randModUint128(r).Inc())
+	}
+	for _, m := range moduli {
+		c := NewBarrett128(m)
+		if !c.Modulus().Eq(m) {
+			t.Errorf("Expected NewBarrett128(%s).Modulus() == %s, got: %s", m, m, c.Modulus())
+		}
+		for i := 0; i < 10; i++ {
+			x, y, e := randModUint128(r), randModUint128(r), randModUint128(r)
+			if result, expected := c.Reduce(x), x.Mod(m); !result.Eq(expected) {
+				t.Errorf("Expected %s mod %s == %s, got: %s", x, m, expected, result)
+			}
+			if result, expected := c.Mul(x, y), x.MulMod(y, m); !result.Eq(expected) {
+				t.Errorf("Expected %s*%s mod %s == %s, got: %s", x, y, m, expected, result)
+			}
+			if result, expected := c.Pow(x, e), x.PowMod(e, m); !result.Eq(expected) {
+				t.Errorf("Expected %s^%s mod %s == %s, got: %s", x, e, m, expected, result)
+			}
+		}
+	}
+}
+
+func TestBarrett128Zero(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Zero modulus did not panic")
+		}
+	}()
+	NewBarrett128(Uint128{hi: 0, lo: 0})
+}
+
+// mulModDivMod is a naive reference for MulMod, built on DivMod
+//
+// The full product hi*2^128 + lo is folded into hi*c + lo, where c = 2^128 mod m, until the high half is zero. Each fold
+// strictly decreases the product, and the remaining low half is reduced with DivMod.
+func mulModDivMod(x, y, m Uint128) Uint128 {
+	_, c := Uint128{hi: maxUint64, lo: maxUint64}.DivMod(m)
+	_, c = c.Inc().DivMod(m)
+	hi, lo := x.mulFull(y)
+	for hi.hi != 0 || hi.lo != 0 {
+		var l Uint128
+		hi, l = hi.mulFull(c)
+		if lo = lo.Add(l); lo.Lt(l) {
+			hi = hi.Inc()
+		}
+	}
+	_, lo = lo.DivMod(m)
+	return lo
+}
+
+func TestMulModDivMod(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		x, y := Uint128{hi: r.Uint64(), lo: r.Uint64()}, Uint128{hi: r.Uint64(), lo: r.Uint64()}
+		m := randModUint128(r).Inc()
+		if i%2 == 0 {
+			m.hi |= 1 << 63
+		}
+		if result, expected := mulModDivMod(x, y, m), x.MulMod(y, m); !result.Eq(expected) {
+			t.Errorf("Expected %s*%s mod %s == %s, got: %s", x, y, m, expected, result)
+		}
+	}
+}
+
+var benchmarkMod = Uint128{hi: maxUint64, lo: maxUint64 - 158}
+
+func BenchmarkMulMod(b *testing.B) {
+	x0, y0 := Uint128{hi: 0x0123456789abcdef, lo: 0xfedcba9876543210}, Uint128{hi: 0xdeadbeef, lo: 0xbaadf00d}
+	b.Run("DivMod", func(b *testing.B) {
+		x := x0
+		for i := 0; i < b.N; i++ {
+			x = mulModDivMod(x, y0, benchmarkMod)
+		}
+	})
+	b.Run("MulMod", func(b *testing.B) {
+		x := x0
+		for i := 0; i < b.N; i++ {
+			x = x.MulMod(y0, benchmarkMod)
+		}
+	})
+	b.Run("Montgomery128", func(b *testing.B) {
+		c := NewMontgomery128(benchmarkMod)
+		x, y := c.ToMont(x0), c.ToMont(y0)
+		for i := 0; i < b.N; i++ {
+			x = c.Mul(x, y)
+		}
+	})
+	b.Run("Barrett128", func(b *testing.B) {
+		c := NewBarrett128(benchmarkMod.Sub(Uint128{lo: 1}))
+		x := x0
+		for i := 0; i < b.N; i++ {
+			x = c.Mul(x, y0)
+		}
+	})
+}
@@ -0,0 +1,272 @@
+package wide
+
+import "math/bits"
+
+// checkLen panics if the slices passed to a slice kernel have different lengths
+func checkLen(n int, lens ...int) {
+	for _, m := range lens {
+		if m != n {
+			panic("wide: slices of different lengths")
+		}
+	}
+}
+
+// checkMask panics if a bitmask is too short for n elements
+func checkMask(dst []uint64, n int) {
+	if len(dst) < (n+63)/64 {
+		panic("wide: bitmask too short")
+	}
+}
+
+// AddInt128Slices sets dst[i] = a[i] + b[i] for each i, overflowing silently
+//
+// AddInt128Slices panics if dst, a and b do not have the same length. dst may alias a or b.
+func AddInt128Slices(dst, a, b []Int128) {
+	checkLen(len(dst), len(a), len(b))
+	a, b = a[:len(dst)], b[:len(dst)]
+	for i := range dst {
+		dst[i] = a[i].Add(b[i])
+	}
+}
+
+// AddSlices sets dst[i] = a[i] + b[i] for each i, overflowing silently
+//
+// AddSlices panics if dst, a and b do not have the same length. dst may alias a or b.
+func AddSlices(dst, a, b []Uint128) {
+	checkLen(len(dst), len(a), len(b))
+	a, b = a[:len(dst)], b[:len(dst)]
+	for i := range dst {
+		dst[i] = a[i].Add(b[i])
+	}
+}
+
+// DotProduct returns the sum of a[i] * b[i], as the high and low halves of a 256-bit integer
+//
+// The sum only overflows 256 bits, silently, for slices with more than 2^128 elements. DotProduct panics if a and b do not
+// have the same length. There is no Int128 counterpart, which would need a signed 256-bit result.
+func DotProduct(a, b []Uint128) (hi, lo Uint128) {
+	checkLen(len(a), len(b))
+	b = b[:len(a)]
+	for i := range a {
+		phi, plo := a[i].mulFull(b[i])
+		hi, lo = add256(hi, lo, phi, plo)
+	}
+	return hi, lo
+}
+
+// EqMask sets bit i of dst when a[i] == x, and clears it otherwise
+//
+// Bit i is bit i%64 of dst[i/64]. The unused bits of the last word are cleared, and the words which follow are unchanged.
+// EqMask panics if dst is shorter than ceil(len(a) / 64).
+func EqMask(dst []uint64, a []Uint128, x Uint128) {
+	checkMask(dst, len(a))
+	for i := 0; i < len(a); i += 64 {
+		chunk := a[i:min(i+64, len(a))]
+		var w uint64
+		for j := range chunk {
+			if chunk[j] == x {
+				w |= 1 << j
+			}
+		}
+		dst[i/64] = w
+	}
+}
+
+// EqMaskInt128 sets bit i of dst when a[i] == x, and clears it otherwise
+//
+// The layout of dst is as in EqMask.
+func EqMaskInt128(dst []uint64, a []Int128, x Int128) {
+	checkMask(dst, len(a))
+	for i := 0; i < len(a); i += 64 {
+		chunk := a[i:min(i+64, len(a))]
+		var w uint64
+		for j := range chunk {
+			if chunk[j] == x {
+				w |= 1 << j
+			}
+		}
+		dst[i/64] = w
+	}
+}
+
+// GtMask sets bit i of dst when a[i] > x, and clears it otherwise
+//
+// The layout of dst is as in EqMask.
+func GtMask(dst []uint64, a []Uint128, x Uint128) {
+	checkMask(dst, len(a))
+	for i := 0; i < len(a); i += 64 {
+		chunk := a[i:min(i+64, len(a))]
+		var w uint64
+		for j := range chunk {
+			if chunk[j].Gt(x) {
+				w |= 1 << j
+			}
+		}
+		dst[i/64] = w
+	}
+}
+
+// GtMaskInt128 sets bit i of dst when a[i] > x, and clears it otherwise
+//
+// The layout of dst is as in EqMask.
+func GtMaskInt128(dst []uint64, a []Int128, x Int128) {
+	checkMask(dst, len(a))
+	for i := 0; i < len(a); i += 64 {
+		chunk := a[i:min(i+64, len(a))]
+		var w uint64
+		for j := range chunk {
+			if chunk[j].Gt(x) {
+				w |= 1 << j
+			}
+		}
+		dst[i/64] = w
+	}
+}
+
+// LtMask sets bit i of dst when a[i] < x, and clears it otherwise
+//
+// The layout of dst is as in EqMask.
+func LtMask(dst []uint64, a []Uint128, x Uint128) {
+	checkMask(dst, len(a))
+	for i := 0; i < len(a); i += 64 {
+		chunk := a[i:min(i+64, len(a))]
+		var w uint64
+		for j := range chunk {
+			if chunk[j].Lt(x) {
+				w |= 1 << j
+			}
+		}
+		dst[i/64] = w
+	}
+}
+
+// LtMaskInt128 sets bit i of dst when a[i] < x, and clears it otherwise
+//
+// The layout of dst is as in EqMask.
+func LtMaskInt128(dst []uint64, a []Int128, x Int128) {
+	checkMask(dst, len(a))
+	for i := 0; i < len(a); i += 64 {
+		chunk := a[i:min(i+64, len(a))]
+		var w uint64
+		for j := range chunk {
+			if chunk[j].Lt(x) {
+				w |= 1 << j
+			}
+		}
+		dst[i/64] = w
+	}
+}
+
+// MinMax returns the minimum and maximum of a slice of Uint128's
+//
+// MinMax panics if a is empty.
+func MinMax(a []Uint128) (lo, hi Uint128) {
+	if len(a) == 0 {
+		panic("wide: MinMax of empty slice")
+	}
+	lo, hi = a[0], a[0]
+	for _, x := range a[1:] {
+		if x.Lt(lo) {
+			lo = x
+		}
+		if x.Gt(hi) {
+			hi = x
+		}
+	}
+	return lo, hi
+}
+
+// MinMaxInt128 returns the minimum and maximum of a slice of Int128's
+//
+// MinMaxInt128 panics if a is empty.
+func MinMaxInt128(a []Int128) (lo, hi Int128) {
+	if len(a) == 0 {
+		panic("wide: MinMaxInt128 of empty slice")
+	}
+	lo, hi = a[0], a[0]
+	for _, x := range a[1:] {
+		if x.Lt(lo) {
+			lo = x
+		}
+		if x.Gt(hi) {
+			hi = x
+		}
+	}
+	return lo, hi
+}
+
+// MulScalar sets dst[i] = a[i] * x for each i, overflowing silently
+//
+// MulScalar panics if dst and a do not have the same length. dst may alias a.
+func MulScalar(dst, a []Uint128, x Uint128) {
+	checkLen(len(dst), len(a))
+	a = a[:len(dst)]
+	for i := range dst {
+		dst[i].hi, dst[i].lo = mul128(a[i].hi, a[i].lo, x.hi, x.lo)
+	}
+}
+
+// MulScalarInt128 sets dst[i] = a[i] * x for each i, overflowing silently
+//
+// MulScalarInt128 panics if dst and a do not have the same length. dst may alias a.
+func MulScalarInt128(dst, a []Int128, x Int128) {
+	checkLen(len(dst), len(a))
+	// The lower 128 bits of a two's complement product are the same as those of the unsigned product
+	a = a[:len(dst)]
+	for i := range dst {
+		hi, lo := mul128(uint64(a[i].hi), a[i].lo, uint64(x.hi), x.lo)
+		dst[i] = Int128{hi: int64(hi), lo: lo}
+	}
+}
+
+// SubInt128Slices sets dst[i] = a[i] - b[i] for each i, overflowing silently
+//
+// SubInt128Slices panics if dst, a and b do not have the same length. dst may alias a or b.
+func SubInt128Slices(dst, a, b []Int128) {
+	checkLen(len(dst), len(a), len(b))
+	a, b = a[:len(dst)], b[:len(dst)]
+	for i := range dst {
+		dst[i] = a[i].Sub(b[i])
+	}
+}
+
+// SubSlices sets dst[i] = a[i] - b[i] for each i, overflowing silently
+//
+// SubSlices panics if dst, a and b do not have the same length. dst may alias a or b.
+func SubSlices(dst, a, b []Uint128) {
+	checkLen(len(dst), len(a), len(b))
+	a, b = a[:len(dst)], b[:len(dst)]
+	for i := range dst {
+		dst[i] = a[i].Sub(b[i])
+	}
+}
+
+// SumInt128Slice returns the sum of a slice of Int128's, and whether it overflows
+//
+// The sum is exact, even if intermediate sums overflow, as long as the final sum fits in an Int128. Otherwise the sum
+// overflows silently, and overflow is true.
+func SumInt128Slice(a []Int128) (sum Int128, overflow bool) {
+	// The sum is accumulated as a 192-bit integer, whose top word is the sign extension of hi when it fits
+	var hi, lo, top uint64
+	for _, x := range a {
+		var c uint64
+		lo, c = bits.Add64(lo, x.lo, 0)
+		hi, c = bits.Add64(hi, uint64(x.hi), c)
+		top += c + uint64(x.hi>>63)
+	}
+	return Int128{hi: int64(hi), lo: lo}, top != uint64(int64(hi)>>63)
+}
+
+// SumSlice returns the sum of a slice of Uint128's, and whether it overflows
+//
+// If the sum overflows, it wraps around modulo 2^128.
+func SumSlice(a []Uint128) (sum Uint128, overflow bool) {
+	var carries uint64
+	for _, x := range a {
+		var c uint64
+		sum.lo, c = bits.Add64(sum.lo, x.lo, 0)
+		sum.hi, c = bits.Add64(sum.hi, x.hi, c)
+		carries |= c
+	}
+	return sum, carries != 0
+}
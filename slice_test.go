@@ -0,0 +1,305 @@
+package wide
+
+import (
+	"math/big"
+	"math/rand/v2"
+	"testing"
+)
+
+// randUint128Slice returns a slice of pseudo-random Uint128's, with a mix of small and large values
+func randUint128Slice(r *rand.Rand, n int) []Uint128 {
+	a := make([]Uint128, n)
+	for i := range a {
+		a[i] = NewUint128(r.Uint64(), r.Uint64()).RShiftN(r.UintN(int128Size))
+	}
+	return a
+}
+
+func TestSliceKernels(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for _, n := range []int{1, 2, 63, 64, 65, 200} {
+		a, b := randUint128Slice(r, n), randUint128Slice(r, n)
+		x := a[r.IntN(n)]
+		sum, diff, prod := make([]Uint128, n), make([]Uint128, n), make([]Uint128, n)
+		AddSlices(sum, a, b)
+		SubSlices(diff, a, b)
+		MulScalar(prod, a, x)
+		eq, lt, gt := make([]uint64, (n+63)/64), make([]uint64, (n+63)/64), make([]uint64, (n+63)/64)
+		EqMask(eq, a, x)
+		LtMask(lt, a, x)
+		GtMask(gt, a, x)
+		bigSum, bigDot := new(big.Int), new(big.Int)
+		min, max := a[0], a[0]
+		for i := range a {
+			if sum[i] != a[i].Add(b[i]) || diff[i] != a[i].Sub(b[i]) || prod[i] != a[i].Mul(x) {
+				t.Fatalf("Expected slice arithmetic on element %d of %s, %s", i, a[i], b[i])
+			}
+			if e := eq[i/64]>>(i%64)&1 == 1; e != (a[i] == x) {
+				t.Errorf("Expected EqMask bit %d == %t", i, a[i] == x)
+			}
+			if l := lt[i/64]>>(i%64)&1 == 1; l != a[i].Lt(x) {
+				t.Errorf("Expected LtMask bit %d == %t", i, a[i].Lt(x))
+			}
+			if g := gt[i/64]>>(i%64)&1 == 1; g != a[i].Gt(x) {
+				t.Errorf("Expected GtMask bit %d == %t", i, a[i].Gt(x))
+			}
+			bigSum.Add(bigSum, bigUint128(a[i]))
+			bigDot.Add(bigDot, new(big.Int).Mul(bigUint128(a[i]), bigUint128(b[i])))
+			if a[i].Lt(min) {
+				min = a[i]
+			}
+			if a[i].Gt(max) {
+				max = a[i]
+			}
+		}
+		if n%64 != 0 && (eq[n/64]|lt[n/64]|gt[n/64])>>(n%64) != 0 {
+			t.Errorf("Expected the unused bits of the bitmasks to be cleared")
+		}
+		if result, overflow := SumSlice(a); bigUint128(result).Cmp(new(big.Int).And(bigSum, bigUint128(Uint128{hi: maxUint64, lo: maxUint64}))) != 0 || overflow != (bigSum.BitLen() > int128Size) {
+			t.Errorf("Expected SumSlice == %#x, got: %s, %t", bigSum, result, overflow)
+		}
+		hi, lo := DotProduct(a, b)
+		if result := new(big.Int).Lsh(bigUint128(hi), int128Size); result.Or(result, bigUint128(lo)).Cmp(bigDot) != 0 {
+			t.Errorf("Expected DotProduct == %#x, got: %#x", bigDot, result)
+		}
+		if lo, hi := MinMax(a); lo != min || hi != max {
+			t.Errorf("Expected MinMax == %s, %s, got: %s, %s", min, max, lo, hi)
+		}
+	}
+}
+
+func TestSumSliceOverflow(t *testing.T) {
+	a := []Uint128{{hi: maxUint64, lo: maxUint64}, {lo: 1}}
+	if sum, overflow := SumSlice(a); sum != (Uint128{}) || !overflow {
+		t.Errorf("Expected SumSlice(%v) == 0x0, true, got: %s, %t", a, sum, overflow)
+	}
+	if sum, overflow := SumSlice(a[:1]); sum != a[0] || overflow {
+		t.Errorf("Expected SumSlice(%v) == %s, false, got: %s, %t", a[:1], a[0], sum, overflow)
+	}
+}
+
+func TestInt128SliceKernels(t *testing.T) {
+	maxInt128, minInt128 := Int128{hi: maxInt64, lo: maxUint64}, Int128{hi: -maxInt64 - 1}
+	one, minusOne := Int128{lo: 1}, Int128{hi: -1, lo: maxUint64}
+	tests := []struct {
+		a        []Int128
+		expected Int128
+		overflow bool
+	}{
+		{nil, Int128{}, false},
+		{[]Int128{minusOne, minusOne}, Int128{hi: -1, lo: maxUint64 - 1}, false},
+		// Intermediate sums overflow, but the final sum fits
+		{[]Int128{maxInt128, one, minusOne}, maxInt128, false},
+		{[]Int128{minInt128, minusOne, one}, minInt128, false},
+		{[]Int128{maxInt128, one}, minInt128, true},
+		{[]Int128{minInt128, minusOne}, maxInt128, true},
+		{[]Int128{minInt128, minInt128, maxInt128, maxInt128}, Int128{hi: -1, lo: maxUint64 - 1}, false},
+	}
+	for _, test := range tests {
+		if sum, overflow := SumInt128Slice(test.a); sum != test.expected || overflow != test.overflow {
+			t.Errorf("Expected SumInt128Slice(%v) == %s, %t, got: %s, %t", test.a, test.expected, test.overflow, sum, overflow)
+		}
+	}
+	a := []Int128{one, minInt128, maxInt128, minusOne}
+	if lo, hi := MinMaxInt128(a); lo != minInt128 || hi != maxInt128 {
+		t.Errorf("Expected MinMaxInt128(%v) == %s, %s, got: %s, %s", a, minInt128, maxInt128, lo, hi)
+	}
+	dst := make([]Int128, len(a))
+	AddInt128Slices(dst, a, a)
+	for i := range a {
+		if dst[i] != a[i].Add(a[i]) {
+			t.Errorf("Expected AddInt128Slices element %d == %s, got: %s", i, a[i].Add(a[i]), dst[i])
+		}
+	}
+	SubInt128Slices(dst, a, []Int128{minusOne, minusOne, one, one})
+	for i, e := range []Int128{{lo: 2}, minInt128.Inc(), maxInt128.Dec(), {hi: -1, lo: maxUint64 - 1}} {
+		if dst[i] != e {
+			t.Errorf("Expected SubInt128Slices element %d == %s, got: %s", i, e, dst[i])
+		}
+	}
+	MulScalarInt128(dst, a, minusOne)
+	for i := range a {
+		if dst[i] != a[i].Neg() {
+			t.Errorf("Expected MulScalarInt128 element %d == %s, got: %s", i, a[i].Neg(), dst[i])
+		}
+	}
+	// The comparisons are signed, so that -1 < 1 even though its two's complement representation is larger
+	var mask [1]uint64
+	masks := []struct {
+		name     string
+		f        func([]uint64, []Int128, Int128)
+		expected uint64
+	}{
+		{"EqMaskInt128", EqMaskInt128, 0b0001},
+		{"GtMaskInt128", GtMaskInt128, 0b0100},
+		{"LtMaskInt128", LtMaskInt128, 0b1010},
+	}
+	for _, m := range masks {
+		if m.f(mask[:], a, one); mask[0] != m.expected {
+			t.Errorf("Expected %s(%v, %s) == %#b, got: %#b", m.name, a, one, m.expected, mask[0])
+		}
+	}
+}
+
+func TestSliceKernelsPanic(t *testing.T) {
+	tests := map[string]func(){
+		"AddSlices":       func() { AddSlices(make([]Uint128, 2), make([]Uint128, 2), make([]Uint128, 1)) },
+		"DotProduct":      func() { DotProduct(make([]Uint128, 2), make([]Uint128, 3)) },
+		"LtMask":          func() { LtMask(make([]uint64, 1), make([]Uint128, 65), Uint128{}) },
+		"MinMax":          func() { MinMax(nil) },
+		"MinMaxInt128":    func() { MinMaxInt128(nil) },
+		"MulScalar":       func() { MulScalar(make([]Uint128, 1), make([]Uint128, 2), Uint128{}) },
+		"AddInt128Slices": func() { AddInt128Slices(make([]Int128, 1), nil, nil) },
+		"GtMaskInt128":    func() { GtMaskInt128(nil, make([]Int128, 1), Int128{}) },
+		"SubInt128Slices": func() { SubInt128Slices(make([]Int128, 2), make([]Int128, 2), nil) },
+		"MulScalarInt128": func() { MulScalarInt128(nil, make([]Int128, 1), Int128{}) },
+	}
+	for name, f := range tests {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("Expected %s to panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+const benchmarkSliceLen = 4096
+
+func BenchmarkAddSlices(b *testing.B) {
+	r := rand.New(rand.NewPCG(1, 2))
+	x, y, dst := randUint128Slice(r, benchmarkSliceLen), randUint128Slice(r, benchmarkSliceLen), make([]Uint128, benchmarkSliceLen)
+	b.Run("kernel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			AddSlices(dst, x, y)
+		}
+	})
+	b.Run("naive", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j := range x {
+				dst[j] = x[j].Add(y[j])
+			}
+		}
+	})
+}
+
+func BenchmarkMulScalar(b *testing.B) {
+	x := randUint128Slice(rand.New(rand.NewPCG(1, 2)), benchmarkSliceLen)
+	dst, y := make([]Uint128, len(x)), x[0]
+	b.Run("kernel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			MulScalar(dst, x, y)
+		}
+	})
+	b.Run("naive", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j := range x {
+				dst[j] = x[j].Mul(y)
+			}
+		}
+	})
+}
+
+func BenchmarkMulScalarInt128(b *testing.B) {
+	u := randUint128Slice(rand.New(rand.NewPCG(1, 2)), benchmarkSliceLen)
+	x := make([]Int128, len(u))
+	for i := range u {
+		x[i] = u[i].Int128()
+	}
+	dst, y := make([]Int128, len(x)), x[0]
+	b.Run("kernel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			MulScalarInt128(dst, x, y)
+		}
+	})
+	b.Run("naive", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j := range x {
+				dst[j] = x[j].Mul(y)
+			}
+		}
+	})
+}
+
+func BenchmarkMinMax(b *testing.B) {
+	x := randUint128Slice(rand.New(rand.NewPCG(1, 2)), benchmarkSliceLen)
+	b.Run("kernel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			MinMax(x)
+		}
+	})
+	b.Run("naive", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			lo, hi := x[0], x[0]
+			for _, v := range x {
+				if v.Cmp(lo) < 0 {
+					lo = v
+				}
+				if v.Cmp(hi) > 0 {
+					hi = v
+				}
+			}
+		}
+	})
+}
+
+func BenchmarkSumSlice(b *testing.B) {
+	x := randUint128Slice(rand.New(rand.NewPCG(1, 2)), benchmarkSliceLen)
+	b.Run("kernel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			SumSlice(x)
+		}
+	})
+	b.Run("naive", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			var sum Uint128
+			overflow := false
+			for _, v := range x {
+				next := sum.Add(v)
+				overflow = overflow || next.Lt(sum)
+				sum = next
+			}
+		}
+	})
+}
+
+func BenchmarkDotProduct(b *testing.B) {
+	r := rand.New(rand.NewPCG(1, 2))
+	x, y := randUint128Slice(r, benchmarkSliceLen), randUint128Slice(r, benchmarkSliceLen)
+	b.Run("kernel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			DotProduct(x, y)
+		}
+	})
+	b.Run("big", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			sum := new(big.Int)
+			for j := range x {
+				sum.Add(sum, new(big.Int).Mul(bigUint128(x[j]), bigUint128(y[j])))
+			}
+		}
+	})
+}
+
+func BenchmarkLtMask(b *testing.B) {
+	x := randUint128Slice(rand.New(rand.NewPCG(1, 2)), benchmarkSliceLen)
+	dst, pivot := make([]uint64, benchmarkSliceLen/64), x[0]
+	b.Run("kernel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			LtMask(dst, x, pivot)
+		}
+	})
+	b.Run("naive", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j := range x {
+				if x[j].Lt(pivot) {
+					dst[j/64] |= 1 << (j % 64)
+				} else {
+					dst[j/64] &^= 1 << (j % 64)
+				}
+			}
+		}
+	})
+}
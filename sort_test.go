@@ -0,0 +1,106 @@
+package wide
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestSortUint128s(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for _, n := range []int{0, 1, 10, radixSortThreshold - 1, radixSortThreshold, 2 * radixSortThreshold} {
+		for _, bits := range []uint{8, 40, 100, 128} {
+			xs := make([]Uint128, n)
+			for i := range xs {
+				xs[i] = NewUint128(r.Uint64(), r.Uint64()).RShiftN(int128Size - bits)
+			}
+			expected := slices.Clone(xs)
+			slices.SortFunc(expected, Uint128.Cmp)
+			SortUint128s(xs)
+			if !slices.Equal(xs, expected) {
+				t.Errorf("Expected SortUint128s to sort %d elements of %d bits", n, bits)
+			}
+			deduped := slices.Compact(slices.Clone(expected))
+			if result := Dedup(slices.Clone(xs)); !slices.Equal(result, deduped) {
+				t.Errorf("Expected Dedup to remove duplicates from %d elements of %d bits", n, bits)
+			}
+			for i := 0; i < 20 && n > 0; i++ {
+				x := xs[r.IntN(n)].Add(Uint128{lo: r.Uint64N(2)})
+				j, found := BinarySearch(xs, x)
+				ej, efound := slices.BinarySearchFunc(xs, x, Uint128.Cmp)
+				if j != ej || found != efound {
+					t.Errorf("Expected BinarySearch(%s) == %d, %t, got: %d, %t", x, ej, efound, j, found)
+				}
+			}
+		}
+	}
+}
+
+func TestSortInt128s(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for _, n := range []int{0, 10, 2 * radixSortThreshold} {
+		xs := make([]Int128, n)
+		for i := range xs {
+			// Values near zero, so that both signs and many duplicates occur
+			xs[i] = Int128FromInt64(r.Int64N(2000) - 1000)
+			if i%3 == 0 {
+				xs[i] = Int128{hi: int64(r.Uint64()), lo: r.Uint64()}
+			}
+		}
+		expected := slices.Clone(xs)
+		slices.SortFunc(expected, Int128.Cmp)
+		SortInt128s(xs)
+		if !slices.Equal(xs, expected) {
+			t.Errorf("Expected SortInt128s to sort %d elements", n)
+		}
+		if result := DedupInt128(slices.Clone(xs)); !slices.Equal(result, slices.Compact(expected)) {
+			t.Errorf("Expected DedupInt128 to remove duplicates from %d elements", n)
+		}
+		if n > 0 {
+			if j, found := BinarySearchInt128(xs, xs[n/2]); !found || xs[j] != xs[n/2] {
+				t.Errorf("Expected BinarySearchInt128(%s) to find it, got: %d, %t", xs[n/2], j, found)
+			}
+		}
+	}
+}
+
+func TestSortByUint128Key(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for _, n := range []int{10, 2 * radixSortThreshold} {
+		keys, values := make([]Uint128, n), make([]int, n)
+		for i := range keys {
+			// Few distinct keys, so that the stability of the sort is tested
+			keys[i], values[i] = Uint128{hi: r.Uint64N(3), lo: r.Uint64N(3)}, i
+		}
+		SortByUint128Key(keys, values)
+		for i := 1; i < n; i++ {
+			if keys[i].Lt(keys[i-1]) || keys[i] == keys[i-1] && values[i] < values[i-1] {
+				t.Fatalf("Expected SortByUint128Key to stably sort %d elements, got: %s, %d after %s, %d", n, keys[i], values[i], keys[i-1], values[i-1])
+			}
+		}
+	}
+}
+
+func BenchmarkSortUint128s(b *testing.B) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for _, n := range []int{1 << 12, 1 << 13, 1 << 14, 1 << 15, 1 << 16, 1 << 17, 1 << 18, 1 << 20} {
+		xs := make([]Uint128, n)
+		for i := range xs {
+			xs[i] = NewUint128(r.Uint64(), r.Uint64())
+		}
+		ys := make([]Uint128, len(xs))
+		b.Run(fmt.Sprintf("n=%d/radix", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				copy(ys, xs)
+				radixSort[struct{}](ys, nil)
+			}
+		})
+		b.Run(fmt.Sprintf("n=%d/pdqsort", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				copy(ys, xs)
+				slices.SortFunc(ys, Uint128.Cmp)
+			}
+		})
+	}
+}
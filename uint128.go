@@ -3,7 +3,9 @@ package wide
 
 import (
 	"fmt"
+	"math"
 	"math/big"
+	mathbits "math/bits"
 	"math/rand"
 
 	"github.com/ryanavella/wide/internal/bits"
@@ -71,6 +73,17 @@ func (x Uint128) AndNot(y Uint128) (z Uint128) {
 	return z
 }
 
+// bigInt returns a Uint128 as a big.Int
+func (x Uint128) bigInt() *big.Int {
+	z := new(big.Int).SetUint64(x.hi)
+	return z.Lsh(z, int64Size).Or(z, new(big.Int).SetUint64(x.lo))
+}
+
+// Cbrt returns the cube root of a Uint128, rounded down
+func (x Uint128) Cbrt() Uint128 {
+	return x.NthRoot(3)
+}
+
 // Cmp compares x and y and returns:
 //
 //   -1 if x <  y
@@ -104,8 +117,8 @@ func (x Uint128) Dec() (z Uint128) {
 
 // Div returns the quotient corresponding to the provided dividend and divisor
 //
-// Div panics on division by 0. It checks some common/faster cases before fully committing to long division. This can probably be further optimized by
-// implementing a successive approximation algorithm, with an initial seed value determined by a 64-bit division of the most significant bits.
+// Div panics on division by 0. It is computed with at most two 128/64 divisions, which use the DIVQ instruction on amd64 unless the
+// purego build tag is set.
 func (x Uint128) Div(d Uint128) (q Uint128) {
 	q, _ = x.DivMod(d)
 	return q
@@ -113,8 +126,8 @@ func (x Uint128) Div(d Uint128) (q Uint128) {
 
 // DivMod returns the quotient and remainder corresponding to the provided dividend and divisor
 //
-// DivMod panics on division by 0. It checks some common/faster cases before fully committing to long division. This can probably be further optimized by
-// implementing a successive approximation algorithm, with an initial seed value determined by a 64-bit division of the most significant bits.
+// DivMod panics on division by 0. It is computed with at most two 128/64 divisions, which use the DIVQ instruction on amd64 unless the
+// purego build tag is set.
 func (x Uint128) DivMod(d Uint128) (q, r Uint128) {
 	// Handle edge cases and some more common/faster cases
 	switch {
@@ -131,36 +144,28 @@ func (x Uint128) DivMod(d Uint128) (q, r Uint128) {
 		r = x.Sub(d)
 		return q, r
 	}
-	/*
-		// Case 4: N and D have 64 leading zero bits
-		case x.hi == 0 && d.hi == 0:
-			q.lo = x.lo / d.lo
-			r.lo = x.lo % d.lo
-			return q, r
-		// Case 5: N and D have 64 trailing zero bits
-		case x.lo == 0 && d.lo == 0:
-			q.lo = x.hi / d.hi
-			// The following remainder calculation can probably be optimized further
-			dq := d.Mul(q)
-			r = x.Sub(dq)
-			return q, r
-	*/
-	n := x.Len() - d.Len()
-	if n < 0 {
-		r = x
+	// Case 4: D fits in 64 bits, so the quotient can be computed with two 128/64 divisions
+	if d.hi == 0 {
+		var rem uint64
+		q.hi, rem = div128by64(0, x.hi, d.lo)
+		q.lo, r.lo = div128by64(rem, x.lo, d.lo)
 		return q, r
 	}
-	d = d.LShiftN(n)
-	var i uint
-	for i = 0; i <= n; i++ {
-		q = q.LShift()
-		if d.Lte(x) {
-			q.lo |= 1
-			x = x.Sub(d)
-		}
-		d = d.RShift()
+	// Case 5: D has 65 to 127 significant bits, so the quotient fits in 64 bits
+	//
+	// The quotient is estimated by dividing N / 2 by the upper 64 bits of D after normalizing it, which is at most 1 too large
+	// once shifted back (Hacker's Delight, section 9-5).
+	s := int64Size - bits.Len64(d.hi)
+	q.lo, _ = div128by64(x.hi>>1, x.hi<<(int64Size-1)|x.lo>>1, d.LShiftN(s).hi)
+	q.lo >>= int64Size - 1 - s
+	if q.lo != 0 {
+		q.lo--
+	}
+	r = x.Sub(q.Mul(d))
+	if r.Gte(d) {
+		q.lo++
+		r = r.Sub(d)
 	}
-	r = x
 	return q, r
 }
 
@@ -169,6 +174,11 @@ func (x Uint128) Eq(y Uint128) bool {
 	return x.hi == y.hi && x.lo == y.lo
 }
 
+// float64 returns the nearest float64 to a Uint128 (up to double rounding)
+func (x Uint128) float64() float64 {
+	return float64(x.hi)*(1<<int64Size) + float64(x.lo)
+}
+
 // Gt returns whether x is greater than y
 func (x Uint128) Gt(y Uint128) bool {
 	switch {
@@ -213,6 +223,11 @@ func (x Uint128) IsInt64() bool {
 	return x.hi == 0 && x.lo <= maxInt64
 }
 
+// IsPowerOfTwo returns whether x is an integer power of two
+func (x Uint128) IsPowerOfTwo() bool {
+	return (x.hi != 0 || x.lo != 0) && x.And(x.Dec()).Eq(Uint128{})
+}
+
 // IsUint64 checks if the Uint128 can be represented as a uint64 without overflowing
 func (x Uint128) IsUint64() bool {
 	return x.hi == 0
@@ -245,6 +260,29 @@ func (x Uint128) Len() uint {
 	return bits.Len64(x.hi) + int64Size
 }
 
+// Log10 returns the base 10 logarithm of a Uint128, rounded down
+//
+// Log10 panics if x is 0.
+func (x Uint128) Log10() uint {
+	// 1233/4096 approximates log10(2) closely enough that n is either exact or one too large
+	n := (x.Log2() + 1) * 1233 >> 12
+	if x.Lt(pow10tab[n]) {
+		n--
+	}
+	return n
+}
+
+// Log2 returns the base 2 logarithm of a Uint128, rounded down
+//
+// Log2 panics if x is 0.
+func (x Uint128) Log2() uint {
+	n := x.Len()
+	if n == 0 {
+		panic("logarithm of zero")
+	}
+	return n - 1
+}
+
 // LShift returns a Uint128 left-shifted by 1
 func (x Uint128) LShift() (z Uint128) {
 	z.hi = x.hi<<1 | x.lo>>(int64Size-1)
@@ -254,18 +292,8 @@ func (x Uint128) LShift() (z Uint128) {
 
 // LShiftN returns a Uint128 left-shifted by a uint (i.e. x << n)
 func (x Uint128) LShiftN(n uint) (z Uint128) {
-	switch {
-	case n >= int128Size:
-		return z // z.hi, z.lo = 0, 0
-	case n >= int64Size:
-		z.hi = x.lo << (n - int64Size)
-		z.lo = 0
-		return z
-	default:
-		z.hi = x.hi<<n | x.lo>>(int64Size-n)
-		z.lo = x.lo << n
-		return z
-	}
+	z.hi, z.lo = shl128(x.hi, x.lo, n)
+	return z
 }
 
 // Lt returns whether x is less than y
@@ -298,8 +326,8 @@ func (x Uint128) Lte(y Uint128) bool {
 
 // Mod returns the remainder corresponding to the provided dividend and divisor
 //
-// Mod panics on division by 0. It checks some common/faster cases before fully committing to long division. This can probably be further optimized by
-// implementing a successive approximation algorithm, with an initial seed value determined by a 64-bit division of the most significant bits.
+// Mod panics on division by 0. It is computed with at most two 128/64 divisions, which use the DIVQ instruction on amd64 unless the
+// purego build tag is set.
 func (x Uint128) Mod(d Uint128) (r Uint128) {
 	_, r = x.DivMod(d)
 	return r
@@ -307,20 +335,25 @@ func (x Uint128) Mod(d Uint128) (r Uint128) {
 
 // Mul returns the product of two Uint128's
 func (x Uint128) Mul(y Uint128) (z Uint128) {
-	var i uint
-	for i = 0; i < int64Size; i++ {
-		if y.lo&(1<<i) != 0 {
-			z = z.Add(x.LShiftN(i))
-		}
-	}
-	for i = 0; i < int64Size; i++ {
-		if y.hi&(1<<i) != 0 {
-			z = z.Add(x.LShiftN(i + int64Size))
-		}
-	}
+	z.hi, z.lo = mul128(x.hi, x.lo, y.hi, y.lo)
 	return z
 }
 
+// mulFull returns the full 256-bit product of two Uint128's, split into its high and low halves
+func (x Uint128) mulFull(y Uint128) (hi, lo Uint128) {
+	h00, l00 := mathbits.Mul64(x.lo, y.lo)
+	h01, l01 := mathbits.Mul64(x.lo, y.hi)
+	h10, l10 := mathbits.Mul64(x.hi, y.lo)
+	h11, l11 := mathbits.Mul64(x.hi, y.hi)
+	mid := Uint128{lo: h00}.Add(Uint128{lo: l01}).Add(Uint128{lo: l10})
+	top := Uint128{lo: h01}.Add(Uint128{lo: h10}).Add(Uint128{lo: l11}).Add(Uint128{lo: mid.hi})
+	hi.hi = h11 + top.hi
+	hi.lo = top.lo
+	lo.hi = mid.lo
+	lo.lo = l00
+	return hi, lo
+}
+
 // Nand returns the bitwise NAND of two Uint128's
 func (x Uint128) Nand(y Uint128) (z Uint128) {
 	z.hi = ^(x.hi & y.hi)
@@ -352,6 +385,42 @@ func (x Uint128) Not() (z Uint128) {
 	return z
 }
 
+// NthRoot returns the n-th root of a Uint128, rounded down
+//
+// NthRoot panics if n is 0. The result is refined by Newton's method, seeded from a float64 estimate.
+func (x Uint128) NthRoot(n uint) Uint128 {
+	switch {
+	case n == 0:
+		panic("zeroth root")
+	case n == 1 || x.hi == 0 && x.lo <= 1:
+		return x
+	case n == 2:
+		return x.Sqrt()
+	case n >= x.Len():
+		return Uint128{lo: 1}
+	}
+	// The estimate is nudged upwards, so that Newton's method converges on the root from above
+	est := math.Pow(x.float64(), 1/float64(n))
+	z := Uint128{lo: uint64(est*(1+1.0/(1<<40))) + 2}
+	if p, ok := z.PowChecked(n); ok && p.Lte(x) {
+		// The estimate is unexpectedly low, so fall back to a power of two which is known to be too large
+		z = Uint128{lo: 1}.LShiftN((x.Len() + n - 1) / n)
+	}
+	nm1 := Uint128{lo: uint64(n - 1)}
+	nn := Uint128{lo: uint64(n)}
+	for {
+		var y Uint128
+		if p, ok := z.PowChecked(n - 1); ok {
+			y = x.Div(p)
+		}
+		y = y.Add(z.Mul(nm1)).Div(nn)
+		if y.Gte(z) {
+			return z
+		}
+		z = y
+	}
+}
+
 // Or returns the bitwise OR of two Uint128's
 func (x Uint128) Or(y Uint128) (z Uint128) {
 	z.hi = x.hi | y.hi
@@ -359,6 +428,41 @@ func (x Uint128) Or(y Uint128) (z Uint128) {
 	return z
 }
 
+// Pow returns x raised to the power n
+//
+// This function overflows silently
+func (x Uint128) Pow(n uint) (z Uint128) {
+	z.lo = 1
+	for n > 0 {
+		if n&1 != 0 {
+			z = z.Mul(x)
+		}
+		n >>= 1
+		x = x.Mul(x)
+	}
+	return z
+}
+
+// PowChecked returns x raised to the power n, and whether the result was computed without overflowing
+func (x Uint128) PowChecked(n uint) (z Uint128, ok bool) {
+	z.lo = 1
+	for n > 0 {
+		var hi Uint128
+		if n&1 != 0 {
+			if hi, z = z.mulFull(x); hi.hi != 0 || hi.lo != 0 {
+				return z, false
+			}
+		}
+		if n >>= 1; n == 0 {
+			break
+		}
+		if hi, x = x.mulFull(x); hi.hi != 0 || hi.lo != 0 {
+			return z, false
+		}
+	}
+	return z, true
+}
+
 // RShift returns a Uint128 right-shifted by 1
 func (x Uint128) RShift() (z Uint128) {
 	z.hi = x.hi >> 1
@@ -368,18 +472,8 @@ func (x Uint128) RShift() (z Uint128) {
 
 // RShiftN returns a Uint128 right-shifted by a uint (i.e. x >> n)
 func (x Uint128) RShiftN(n uint) (z Uint128) {
-	switch {
-	case n >= int128Size:
-		return z // zhi, zlo = 0, 0
-	case n >= int64Size:
-		z.hi = 0
-		z.lo = x.hi >> (n - int64Size)
-		return z
-	default:
-		z.hi = x.hi >> n
-		z.lo = x.lo>>n | x.hi<<(int64Size-n)
-		return z
-	}
+	z.hi, z.lo = shr128(x.hi, x.lo, n)
+	return z
 }
 
 // RShift128 returns a Uint128 right-shifted by a Uint128 (i.e. x >> y)
@@ -390,6 +484,29 @@ func (x Uint128) RShift128(y Uint128) (z Uint128) {
 	return x.RShiftN(uint(y.lo))
 }
 
+// Sqrt returns the square root of a Uint128, rounded down
+//
+// The result is refined by Newton's method, seeded from a float64 estimate.
+func (x Uint128) Sqrt() Uint128 {
+	if x.hi == 0 && x.lo <= 1 {
+		return x
+	}
+	// The float64 estimate is accurate to within a few thousand, so nudging it upwards
+	// ensures that Newton's method converges on the root from above
+	est := math.Sqrt(x.float64())
+	z := Uint128{lo: maxUint64}
+	if est < 1<<int64Size-1<<12 {
+		z.lo = uint64(est) + 1<<12
+	}
+	for {
+		y := z.Add(x.Div(z)).RShift()
+		if y.Gte(z) {
+			return z
+		}
+		z = y
+	}
+}
+
 // Sub returns the difference of two Uint128's
 func (x Uint128) Sub(y Uint128) (z Uint128) {
 	z.hi = x.hi - y.hi
@@ -400,6 +517,17 @@ func (x Uint128) Sub(y Uint128) (z Uint128) {
 	return z
 }
 
+// TrailingZeros returns the number of trailing zero bits in x
+//
+// Edge cases:
+//   Uint128{0, 0}.TrailingZeros() -> 128
+func (x Uint128) TrailingZeros() uint {
+	if x.lo == 0 {
+		return uint(mathbits.TrailingZeros64(x.hi)) + int64Size
+	}
+	return uint(mathbits.TrailingZeros64(x.lo))
+}
+
 // Uint64 returns a representation of the Uint128 as the builtin uint64
 //
 // This function overflows silently
@@ -1,9 +1,18 @@
 package wide
 
 import (
+	"math/big"
+	"math/rand"
 	"testing"
 )
 
+// bigUint128 returns a big.Int representation of a Uint128
+func bigUint128(x Uint128) *big.Int {
+	z := new(big.Int).SetUint64(x.hi)
+	z.Lsh(z, int64Size)
+	return z.Or(z, new(big.Int).SetUint64(x.lo))
+}
+
 func TestStringUint128(t *testing.T) {
 	tests := []struct {
 		inp      Uint128
@@ -84,6 +93,29 @@ func TestAndNotUint128(t *testing.T) {
 	}
 }
 
+func TestCbrtUint128(t *testing.T) {
+	tests := []struct {
+		inp      Uint128
+		expected Uint128
+	}{
+		{Uint128{hi: 0, lo: 0}, Uint128{hi: 0, lo: 0}},
+		{Uint128{hi: 0, lo: 1}, Uint128{hi: 0, lo: 1}},
+		{Uint128{hi: 0, lo: 7}, Uint128{hi: 0, lo: 1}},
+		{Uint128{hi: 0, lo: 8}, Uint128{hi: 0, lo: 2}},
+		{Uint128{hi: 0, lo: 26}, Uint128{hi: 0, lo: 2}},
+		{Uint128{hi: 0, lo: 27}, Uint128{hi: 0, lo: 3}},
+		{Uint128{hi: 1 << 62, lo: 0}, Uint128{hi: 0, lo: 1 << 42}},
+		{Uint128{hi: 1<<62 - 1, lo: maxUint64}, Uint128{hi: 0, lo: 1<<42 - 1}},
+		{Uint128{hi: maxUint64, lo: maxUint64}, Uint128{hi: 0, lo: 6981463658331}},
+	}
+	for _, test := range tests {
+		result := test.inp.Cbrt()
+		if !result.Eq(test.expected) {
+			t.Errorf("Expected %s.Cbrt() == %s, got: %s", test.inp, test.expected, result)
+		}
+	}
+}
+
 func TestCmpUint128(t *testing.T) {
 	tests := []struct {
 		op1      Uint128
@@ -281,6 +313,29 @@ func TestIsInt64Uint128(t *testing.T) {
 	}
 }
 
+func TestIsPowerOfTwoUint128(t *testing.T) {
+	tests := []struct {
+		inp      Uint128
+		expected bool
+	}{
+		{Uint128{hi: 0, lo: 0}, false},
+		{Uint128{hi: 0, lo: 1}, true},
+		{Uint128{hi: 0, lo: 2}, true},
+		{Uint128{hi: 0, lo: 3}, false},
+		{Uint128{hi: 0, lo: 1 << 63}, true},
+		{Uint128{hi: 1, lo: 0}, true},
+		{Uint128{hi: 1, lo: 1}, false},
+		{Uint128{hi: 1 << 63, lo: 0}, true},
+		{Uint128{hi: maxUint64, lo: maxUint64}, false},
+	}
+	for _, test := range tests {
+		result := test.inp.IsPowerOfTwo()
+		if test.expected != result {
+			t.Errorf("Expected %s.IsPowerOfTwo() == %v, got: %v", test.inp, test.expected, result)
+		}
+	}
+}
+
 func TestIsUint64Uint128(t *testing.T) {
 	tests := []struct {
 		inp      Uint128
@@ -346,6 +401,68 @@ func TestLenUint128(t *testing.T) {
 	}
 }
 
+func TestLog10Uint128(t *testing.T) {
+	tests := []struct {
+		inp      Uint128
+		expected uint
+	}{
+		{Uint128{hi: 0, lo: 1}, 0},
+		{Uint128{hi: 0, lo: 9}, 0},
+		{Uint128{hi: 0, lo: 10}, 1},
+		{Uint128{hi: 0, lo: 99}, 1},
+		{Uint128{hi: 0, lo: 100}, 2},
+		{Uint128{hi: 0, lo: 9999999999999999999}, 18},
+		{Uint128{hi: 0, lo: 10000000000000000000}, 19},
+		{Uint128{hi: 0, lo: maxUint64}, 19},
+		{Uint128{hi: 0x4b3b4ca85a86c47a, lo: 0x098a223fffffffff}, 37},
+		{Uint128{hi: 0x4b3b4ca85a86c47a, lo: 0x098a224000000000}, 38},
+		{Uint128{hi: maxUint64, lo: maxUint64}, 38},
+	}
+	for _, test := range tests {
+		result := test.inp.Log10()
+		if test.expected != result {
+			t.Errorf("Expected %s.Log10() == %v, got: %v", test.inp, test.expected, result)
+		}
+	}
+	for i := uint(1); i < uint(len(pow10tab)); i++ {
+		if result := pow10tab[i].Log10(); result != i {
+			t.Errorf("Expected %s.Log10() == %v, got: %v", pow10tab[i], i, result)
+		}
+		if result := pow10tab[i].Dec().Log10(); result != i-1 {
+			t.Errorf("Expected %s.Log10() == %v, got: %v", pow10tab[i].Dec(), i-1, result)
+		}
+	}
+}
+
+func TestLog2Uint128(t *testing.T) {
+	tests := []struct {
+		inp      Uint128
+		expected uint
+	}{
+		{Uint128{hi: 0, lo: 1}, 0},
+		{Uint128{hi: 0, lo: 2}, 1},
+		{Uint128{hi: 0, lo: 3}, 1},
+		{Uint128{hi: 0, lo: maxUint64}, 63},
+		{Uint128{hi: 1, lo: 0}, 64},
+		{Uint128{hi: maxUint64, lo: maxUint64}, 127},
+	}
+	for _, test := range tests {
+		result := test.inp.Log2()
+		if test.expected != result {
+			t.Errorf("Expected %s.Log2() == %v, got: %v", test.inp, test.expected, result)
+		}
+	}
+}
+
+func TestLog2ZeroUint128(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Logarithm of 0 did not panic")
+		}
+	}()
+	Uint128{hi: 0, lo: 0}.Log2()
+}
+
 func TestLShiftUint128(t *testing.T) {
 	tests := []struct {
 		inp      Uint128
@@ -544,6 +661,47 @@ func TestNotUint128(t *testing.T) {
 	}
 }
 
+func TestNthRootUint128(t *testing.T) {
+	tests := []struct {
+		op1      Uint128
+		op2      uint
+		expected Uint128
+	}{
+		{Uint128{hi: 0, lo: 0}, 5, Uint128{hi: 0, lo: 0}},
+		{Uint128{hi: 0, lo: 12345}, 1, Uint128{hi: 0, lo: 12345}},
+		{Uint128{hi: 0, lo: 31}, 5, Uint128{hi: 0, lo: 1}},
+		{Uint128{hi: 0, lo: 32}, 5, Uint128{hi: 0, lo: 2}},
+		{Uint128{hi: 1, lo: 0}, 4, Uint128{hi: 0, lo: 1 << 16}},
+		{Uint128{hi: 0, lo: maxUint64}, 4, Uint128{hi: 0, lo: 1<<16 - 1}},
+		{Uint128{hi: maxUint64, lo: maxUint64}, 127, Uint128{hi: 0, lo: 2}},
+		{Uint128{hi: maxUint64, lo: maxUint64}, 128, Uint128{hi: 0, lo: 1}},
+		{Uint128{hi: maxUint64, lo: maxUint64}, 1000, Uint128{hi: 0, lo: 1}},
+	}
+	for _, test := range tests {
+		result := test.op1.NthRoot(test.op2)
+		if !result.Eq(test.expected) {
+			t.Errorf("Expected %s.NthRoot(%v) == %s, got: %s", test.op1, test.op2, test.expected, result)
+		}
+	}
+}
+
+func TestNthRootRandUint128(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		x := Uint128{hi: r.Uint64(), lo: r.Uint64()}.RShiftN(uint(r.Intn(int128Size)))
+		n := uint(r.Intn(10) + 2)
+		z := x.NthRoot(n)
+		// z^n <= x < (z+1)^n
+		bx := bigUint128(x)
+		bn := big.NewInt(int64(n))
+		lo := new(big.Int).Exp(bigUint128(z), bn, nil)
+		hi := new(big.Int).Exp(bigUint128(z.Inc()), bn, nil)
+		if lo.Cmp(bx) > 0 || hi.Cmp(bx) <= 0 {
+			t.Errorf("%s.NthRoot(%v) returned incorrect result %s", x, n, z)
+		}
+	}
+}
+
 func TestOrUint128(t *testing.T) {
 	tests := []struct {
 		op1      Uint128
@@ -563,6 +721,56 @@ func TestOrUint128(t *testing.T) {
 	}
 }
 
+func TestPowUint128(t *testing.T) {
+	tests := []struct {
+		op1      Uint128
+		op2      uint
+		expected Uint128
+	}{
+		{Uint128{hi: 0, lo: 0}, 0, Uint128{hi: 0, lo: 1}},
+		{Uint128{hi: 0, lo: 0}, 3, Uint128{hi: 0, lo: 0}},
+		{Uint128{hi: 0, lo: 3}, 0, Uint128{hi: 0, lo: 1}},
+		{Uint128{hi: 0, lo: 3}, 4, Uint128{hi: 0, lo: 81}},
+		{Uint128{hi: 0, lo: 2}, 64, Uint128{hi: 1, lo: 0}},
+		{Uint128{hi: 0, lo: 2}, 127, Uint128{hi: 1 << 63, lo: 0}},
+		{Uint128{hi: 0, lo: 2}, 128, Uint128{hi: 0, lo: 0}},
+		{Uint128{hi: 0, lo: 10}, 38, Uint128{hi: 0x4b3b4ca85a86c47a, lo: 0x098a224000000000}},
+		{Uint128{hi: maxUint64, lo: maxUint64}, 2, Uint128{hi: 0, lo: 1}},
+		{Uint128{hi: maxUint64, lo: maxUint64}, 3, Uint128{hi: maxUint64, lo: maxUint64}},
+	}
+	for _, test := range tests {
+		result := test.op1.Pow(test.op2)
+		if !result.Eq(test.expected) {
+			t.Errorf("Expected %s.Pow(%v) == %s, got: %s", test.op1, test.op2, test.expected, result)
+		}
+	}
+}
+
+func TestPowCheckedUint128(t *testing.T) {
+	tests := []struct {
+		op1       Uint128
+		op2       uint
+		expected1 Uint128
+		expected2 bool
+	}{
+		{Uint128{hi: 0, lo: 0}, 0, Uint128{hi: 0, lo: 1}, true},
+		{Uint128{hi: 0, lo: 3}, 4, Uint128{hi: 0, lo: 81}, true},
+		{Uint128{hi: 0, lo: 2}, 127, Uint128{hi: 1 << 63, lo: 0}, true},
+		{Uint128{hi: 0, lo: 2}, 128, Uint128{}, false},
+		{Uint128{hi: 0, lo: 10}, 38, Uint128{hi: 0x4b3b4ca85a86c47a, lo: 0x098a224000000000}, true},
+		{Uint128{hi: 0, lo: 10}, 39, Uint128{}, false},
+		{Uint128{hi: 0, lo: maxUint64}, 2, Uint128{hi: maxUint64 - 1, lo: 1}, true},
+		{Uint128{hi: 1, lo: 0}, 2, Uint128{}, false},
+		{Uint128{hi: maxUint64, lo: maxUint64}, 1, Uint128{hi: maxUint64, lo: maxUint64}, true},
+	}
+	for _, test := range tests {
+		result1, result2 := test.op1.PowChecked(test.op2)
+		if result2 != test.expected2 || result2 && !result1.Eq(test.expected1) {
+			t.Errorf("Expected %s.PowChecked(%v) == %s, %v got: %s, %v", test.op1, test.op2, test.expected1, test.expected2, result1, result2)
+		}
+	}
+}
+
 func TestRShiftUint128(t *testing.T) {
 	tests := []struct {
 		inp      Uint128
@@ -613,6 +821,41 @@ func TestRShiftNUint128(t *testing.T) {
 	}
 }
 
+func TestSqrtUint128(t *testing.T) {
+	tests := []struct {
+		inp      Uint128
+		expected Uint128
+	}{
+		{Uint128{hi: 0, lo: 0}, Uint128{hi: 0, lo: 0}},
+		{Uint128{hi: 0, lo: 1}, Uint128{hi: 0, lo: 1}},
+		{Uint128{hi: 0, lo: 3}, Uint128{hi: 0, lo: 1}},
+		{Uint128{hi: 0, lo: 4}, Uint128{hi: 0, lo: 2}},
+		{Uint128{hi: 0, lo: maxUint64}, Uint128{hi: 0, lo: 1<<32 - 1}},
+		{Uint128{hi: 1, lo: 0}, Uint128{hi: 0, lo: 1 << 32}},
+		{Uint128{hi: maxUint64 - 1, lo: 0}, Uint128{hi: 0, lo: maxUint64 - 1}},
+		{Uint128{hi: maxUint64 - 1, lo: 1}, Uint128{hi: 0, lo: maxUint64}},
+		{Uint128{hi: maxUint64, lo: maxUint64}, Uint128{hi: 0, lo: maxUint64}},
+	}
+	for _, test := range tests {
+		result := test.inp.Sqrt()
+		if !result.Eq(test.expected) {
+			t.Errorf("Expected %s.Sqrt() == %s, got: %s", test.inp, test.expected, result)
+		}
+	}
+}
+
+func TestSqrtRandUint128(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		x := Uint128{hi: r.Uint64(), lo: r.Uint64()}.RShiftN(uint(r.Intn(int128Size)))
+		result := bigUint128(x.Sqrt())
+		expected := new(big.Int).Sqrt(bigUint128(x))
+		if result.Cmp(expected) != 0 {
+			t.Errorf("Expected %s.Sqrt() == %#x, got: %#x", x, expected, result)
+		}
+	}
+}
+
 func TestSubUint128(t *testing.T) {
 	tests := []struct {
 		expected Uint128
@@ -638,6 +881,27 @@ func TestSubUint128(t *testing.T) {
 	}
 }
 
+func TestTrailingZerosUint128(t *testing.T) {
+	tests := []struct {
+		inp      Uint128
+		expected uint
+	}{
+		{Uint128{hi: 0, lo: 0}, 128},
+		{Uint128{hi: 0, lo: 1}, 0},
+		{Uint128{hi: 0, lo: 12}, 2},
+		{Uint128{hi: 0, lo: 1 << 63}, 63},
+		{Uint128{hi: 1, lo: 0}, 64},
+		{Uint128{hi: 1 << 63, lo: 0}, 127},
+		{Uint128{hi: maxUint64, lo: maxUint64}, 0},
+	}
+	for _, test := range tests {
+		result := test.inp.TrailingZeros()
+		if test.expected != result {
+			t.Errorf("Expected %s.TrailingZeros() == %v, got: %v", test.inp, test.expected, result)
+		}
+	}
+}
+
 func TestUint64Uint128(t *testing.T) {
 	tests := []struct {
 		inp      Uint128
@@ -0,0 +1,119 @@
+package wide
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestUUID(t *testing.T) {
+	tests := []struct {
+		x        Uint128
+		expected string
+		version  int
+		variant  int
+	}{
+		{Uint128{}, "00000000-0000-0000-0000-000000000000", 0, 0b0},
+		{Uint128{hi: 1<<64 - 1, lo: 1<<64 - 1}, "ffffffff-ffff-ffff-ffff-ffffffffffff", 15, 0b111},
+		// Examples from RFC 9562, Appendix A
+		{Uint128{hi: 0xc232ab00941411ec, lo: 0xb3c89f6bdeced846}, "c232ab00-9414-11ec-b3c8-9f6bdeced846", 1, 0b10},
+		{Uint128{hi: 0x919108f752d14320, lo: 0x9bacf847db4148a8}, "919108f7-52d1-4320-9bac-f847db4148a8", 4, 0b10},
+		{Uint128{hi: 0x017f22e279b07cc3, lo: 0x98c4dc0c0c07398f}, "017f22e2-79b0-7cc3-98c4-dc0c0c07398f", 7, 0b10},
+		{Uint128{hi: 0x0123456789ab0def, lo: 0xc123456789abcdef}, "01234567-89ab-0def-c123-456789abcdef", 0, 0b110},
+	}
+	for _, test := range tests {
+		if result := test.x.UUID(); result != test.expected {
+			t.Errorf("Expected %s.UUID() == %s, got: %s", test.x, test.expected, result)
+		}
+		if result, err := ParseUUID(test.expected); err != nil || result != test.x {
+			t.Errorf("Expected ParseUUID(%q) == %s, got: %s, %v", test.expected, test.x, result, err)
+		}
+		if result := test.x.UUIDVersion(); result != test.version {
+			t.Errorf("Expected %s.UUIDVersion() == %d, got: %d", test.x, test.version, result)
+		}
+		if result := test.x.UUIDVariant(); result != test.variant {
+			t.Errorf("Expected %s.UUIDVariant() == %#b, got: %#b", test.x, test.variant, result)
+		}
+	}
+	for _, inp := range []string{"URN:UUID:919108F7-52D1-4320-9BAC-F847DB4148A8", "urn:uuid:919108f7-52d1-4320-9bac-f847db4148a8"} {
+		if result, err := ParseUUID(inp); err != nil || result != tests[3].x {
+			t.Errorf("Expected ParseUUID(%q) == %s, got: %s, %v", inp, tests[3].x, result, err)
+		}
+	}
+	for _, inp := range []string{"", "919108f7-52d1-4320-9bac-f847db4148a", "919108f7-52d1-4320-9bac-f847db4148a88", "919108f7_52d1-4320-9bac-f847db4148a8",
+		"919108f752d143209bacf847db4148a8", "{919108f7-52d1-4320-9bac-f847db4148a8}", "919108g7-52d1-4320-9bac-f847db4148a8", "urn:919108f7-52d1-4320-9bac-f847db4148a8"} {
+		if result, err := ParseUUID(inp); err == nil {
+			t.Errorf("Expected ParseUUID(%q) to fail, got: %s", inp, result)
+		}
+	}
+}
+
+func TestUUIDv8(t *testing.T) {
+	x := UUIDv8(Uint128{hi: 1<<64 - 1, lo: 0})
+	if expected := "ffffffff-ffff-8fff-8000-000000000000"; x.UUID() != expected {
+		t.Errorf("Expected UUIDv8(0xffffffffffffffff0000000000000000) == %s, got: %s", expected, x.UUID())
+	}
+}
+
+func TestUUIDGenerator(t *testing.T) {
+	// The example timestamp of RFC 9562, Tuesday, February 22, 2022 2:22:22.00 PM GMT-05:00
+	now := time.UnixMilli(0x017f22e279b0)
+	clock := func() time.Time { return now }
+	g1 := NewUUIDGenerator(clock, NewPCG64(Uint128FromUint64(1), Uint128{}))
+	g2 := NewUUIDGenerator(clock, NewPCG64(Uint128FromUint64(1), Uint128{}))
+	var last7, last8 Uint128
+	for i := 0; i < 100; i++ {
+		if i == 50 {
+			// The clock goes backwards
+			now = now.Add(-time.Second)
+		}
+		v4, v7, v8 := g1.V4(), g1.V7(), g1.V8()
+		if v4 != g2.V4() || v7 != g2.V7() || v8 != g2.V8() {
+			t.Fatalf("Expected UUIDGenerator with the same clock and source to be deterministic")
+		}
+		for _, test := range []struct {
+			x       Uint128
+			version int
+		}{{v4, 4}, {v7, 7}, {v8, 8}} {
+			if test.x.UUIDVersion() != test.version || test.x.UUIDVariant() != 0b10 {
+				t.Errorf("Expected UUIDv%d, got: %s", test.version, test.x.UUID())
+			}
+		}
+		if v7.hi>>16 != 0x017f22e279b0 {
+			t.Errorf("Expected UUIDv7 with timestamp 017f22e2-79b0, got: %s", v7.UUID())
+		}
+		if !v7.Gt(last7) || !v8.Gt(last8) {
+			t.Errorf("Expected UUIDv7 and UUIDv8 to increase, got: %s after %s, and %s after %s", v7.UUID(), last7.UUID(), v8.UUID(), last8.UUID())
+		}
+		last7, last8 = v7, v8
+	}
+	if ns := last8.hi>>16<<16 | (last8.hi&0xfff)<<4 | last8.lo>>58&0xf; ns != uint64(now.Add(time.Second).UnixNano())+99 {
+		t.Errorf("Expected UUIDv8 with timestamp %d, got: %d", now.Add(time.Second).UnixNano()+99, ns)
+	}
+}
+
+func TestUUIDGeneratorConcurrent(t *testing.T) {
+	var g UUIDGenerator
+	const n, m = 8, 1000
+	results := make([][]Uint128, n)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < m; j++ {
+				results[i] = append(results[i], g.V7())
+			}
+		}(i)
+	}
+	wg.Wait()
+	seen := make(map[Uint128]bool)
+	for _, r := range results {
+		for j, x := range r {
+			if seen[x] || j > 0 && !x.Gt(r[j-1]) {
+				t.Fatalf("Expected unique and increasing UUIDv7's, got: %s", x.UUID())
+			}
+			seen[x] = true
+		}
+	}
+}